@@ -0,0 +1,276 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"tubtub/internal/guesser"
+)
+
+const datasetUsage = `usage: tubtub dataset <command> [flags]
+
+commands:
+  lint [path]    validate a pack's dataset.json and report every problem
+  vocab [path]   list values that are missing from the pack's vocabulary.json
+  export --csv out.csv [path]            write the dataset as CSV ("-" for stdout)
+  import --csv in.csv [--write] [path]   preview (or with --write, apply) a CSV import
+  diff [--json] old.json new.json        list games added, removed and changed between two versions
+  dupes [--min 0.6] [--json] [path]      list likely duplicate games with a confidence score
+
+path defaults to the default pack (TUBTUB_DEFAULT_PACK, "games").
+`
+
+func runDatasetCommand(root string, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, datasetUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "lint":
+		return runDatasetLint(root, args[1:])
+	case "vocab":
+		return runDatasetVocab(root, args[1:])
+	case "export":
+		return runDatasetExport(root, args[1:])
+	case "import":
+		return runDatasetImport(root, args[1:])
+	case "diff":
+		return runDatasetDiff(args[1:])
+	case "dupes":
+		return runDatasetDupes(root, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown dataset command %q\n\n%s", args[0], datasetUsage)
+		return 2
+	}
+}
+
+func runDatasetLint(root string, args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	quiet := fs.Bool("quiet", false, "only print errors, not warnings")
+	fs.Parse(args)
+
+	path := defaultDatasetPath(root)
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	report, err := guesser.LintDataset(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lint: %v\n", err)
+		return 1
+	}
+
+	for _, issue := range report.Issues {
+		if *quiet && issue.Severity != guesser.SeverityError {
+			continue
+		}
+		fmt.Println(issue)
+	}
+	fmt.Printf("%s: %d error(s), %d warning(s)\n", path, report.ErrorCount(), report.WarningCount())
+
+	if report.HasErrors() {
+		return 1
+	}
+	return 0
+}
+
+func runDatasetVocab(root string, args []string) int {
+	fs := flag.NewFlagSet("vocab", flag.ExitOnError)
+	fs.Parse(args)
+
+	path := defaultDatasetPath(root)
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	misses, err := guesser.CheckVocabulary(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "vocab: %v\n", err)
+		return 1
+	}
+
+	for _, m := range misses {
+		fmt.Printf("%-22s %-40q ids=%v\n", m.Field, m.Value, m.GameIDs)
+	}
+	fmt.Printf("%s: %d value(s) outside the vocabulary\n", path, len(misses))
+	return 0
+}
+
+func runDatasetExport(root string, args []string) int {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	out := fs.String("csv", "", "CSV file to write, or - for stdout")
+	delim := fs.String("delim", guesser.DefaultListDelimiter, "separator for list fields inside a cell")
+	fs.Parse(args)
+
+	if *out == "" {
+		fmt.Fprintln(os.Stderr, "export: --csv is required")
+		return 2
+	}
+	path := defaultDatasetPath(root)
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	w := os.Stdout
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "export: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := guesser.ExportCSV(path, w, *delim); err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func runDatasetImport(root string, args []string) int {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	in := fs.String("csv", "", "CSV file to import")
+	delim := fs.String("delim", guesser.DefaultListDelimiter, "separator for list fields inside a cell")
+	write := fs.Bool("write", false, "apply the import instead of only previewing it")
+	fs.Parse(args)
+
+	if *in == "" {
+		fmt.Fprintln(os.Stderr, "import: --csv is required")
+		return 2
+	}
+	path := defaultDatasetPath(root)
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	f, err := os.Open(*in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	plan, err := guesser.PlanCSVImport(path, f, *delim)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import: %v\n", err)
+		return 1
+	}
+
+	added := 0
+	for _, c := range plan.Changes {
+		if c.Added {
+			added++
+			fmt.Printf("+ %-4d %s\n", c.GameID, c.Name)
+		} else {
+			fmt.Printf("~ %-4d %s: %s\n", c.GameID, c.Name, strings.Join(c.Fields, ", "))
+		}
+	}
+	fmt.Printf("%d added, %d changed, %d unchanged", added, len(plan.Changes)-added, plan.Unchanged)
+	if n := len(plan.NotInCSV); n > 0 {
+		fmt.Printf(", %d not in the CSV (kept)", n)
+	}
+	fmt.Println()
+
+	if !*write {
+		if len(plan.Changes) > 0 {
+			fmt.Println("preview only; re-run with --write to apply")
+		}
+		return 0
+	}
+
+	actor := os.Getenv("USER")
+	if actor == "" {
+		actor = "csv-import"
+	}
+	if err := plan.Apply(actor); err != nil {
+		fmt.Fprintf(os.Stderr, "import: %v\n", err)
+		return 1
+	}
+	fmt.Printf("wrote %s\n", path)
+	return 0
+}
+
+func runDatasetDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the diff as JSON")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "diff: need old and new dataset paths")
+		return 2
+	}
+
+	diff, err := guesser.DiffDatasetFiles(fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(diff)
+		return 0
+	}
+
+	for _, g := range diff.Added {
+		fmt.Printf("+ %-4d %s\n", g.ID, g.Name)
+	}
+	for _, g := range diff.Removed {
+		fmt.Printf("- %-4d %s\n", g.ID, g.Name)
+	}
+	for _, c := range diff.Changed {
+		fmt.Printf("~ %-4d %s\n", c.ID, c.Name)
+		for _, f := range c.Changes {
+			fmt.Printf("      %s: %v -> %v\n", f.Field, formatDiffValue(f.Old), formatDiffValue(f.New))
+		}
+	}
+	fmt.Printf("%d added, %d removed, %d changed\n", len(diff.Added), len(diff.Removed), len(diff.Changed))
+	return 0
+}
+
+func formatDiffValue(v interface{}) string {
+	if v == nil {
+		return "(none)"
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func runDatasetDupes(root string, args []string) int {
+	fs := flag.NewFlagSet("dupes", flag.ExitOnError)
+	threshold := fs.Float64("min", guesser.DefaultDuplicateThreshold, "lowest confidence to report (0-1)")
+	asJSON := fs.Bool("json", false, "print the candidates as JSON")
+	fs.Parse(args)
+
+	path := defaultDatasetPath(root)
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	dupes, err := guesser.FindDatasetDuplicates(path, *threshold)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "dupes: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(dupes)
+		return 0
+	}
+
+	for _, d := range dupes {
+		fmt.Printf("%.2f  %d %q  <>  %d %q\n", d.Confidence, d.A.ID, d.A.Name, d.B.ID, d.B.Name)
+		fmt.Printf("      %s\n", strings.Join(d.Reasons, ", "))
+	}
+	fmt.Printf("%s: %d candidate pair(s) at %.2f or above\n", path, len(dupes), *threshold)
+	return 0
+}
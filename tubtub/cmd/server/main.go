@@ -4,7 +4,10 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	"tubtub/internal/guesser"
 	"tubtub/internal/webutil"
@@ -35,20 +38,61 @@ func projectRoot() string {
 	return "."
 }
 
-func main() {
+func resolveRoot() string {
 	root := os.Getenv("TUBTUB_ROOT")
 	if root == "" {
 		root = projectRoot()
 	}
+	return root
+}
+
+func packsDir(root string) string {
+	return filepath.Join(root, "web", "guesser", "packs")
+}
+
+func defaultPack() string {
+	if p := os.Getenv("TUBTUB_DEFAULT_PACK"); p != "" {
+		return p
+	}
+	return "games"
+}
+
+func defaultDatasetPath(root string) string {
+	return filepath.Join(packsDir(root), defaultPack(), "dataset.json")
+}
+
+func main() {
+	root := resolveRoot()
+
+	// `tubtub dataset ...` runs a maintenance command instead of the server
+	if len(os.Args) > 1 && os.Args[1] == "dataset" {
+		os.Exit(runDatasetCommand(root, os.Args[2:]))
+	}
+
 	log.Printf("Using project root: %s\n", root)
 
-	datasetPath := filepath.Join(root, "web", "guesser", "games.json")
-	idx, err := guesser.LoadDataset(datasetPath)
+	library, err := guesser.OpenLibrary(packsDir(root), defaultPack())
 	if err != nil {
 		log.Fatalf("cannot load dataset: %v", err)
 	}
+	log.Printf("Loaded dataset packs: %v (default %s)\n", library.Names(), library.Default())
+
+	// reload on SIGHUP or when a pack's files change on disk
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			log.Println("SIGHUP: reloading dataset packs")
+			library.ReloadAll()
+		}
+	}()
+	library.Watch(5*time.Second, nil)
 
-	sessionStore := guesser.NewSessionStore(idx)
+	sessionStore := guesser.NewSessionStore()
+	// finished dream games; kept out of deploys like the pack backups
+	dreamStore := guesser.NewDreamStore(filepath.Join(root, "web", "guesser", "dreams"))
+	dreamPitches := guesser.NewPitchBook(filepath.Join(root, "web", "guesser", "dream_pitch.json"))
+	adminToken := os.Getenv("TUBTUB_ADMIN_TOKEN")
 
 	mux := http.NewServeMux()
 
@@ -77,24 +121,58 @@ func main() {
 	// -----------------------------
 	// API: Guessing game
 	// -----------------------------
-	mux.Handle("/api/guess/start", guesser.GuessStartHandler(idx, sessionStore))
-	mux.Handle("/api/guess/categories", guesser.GuessCategoriesHandler(idx, sessionStore))
-	mux.Handle("/api/guess/reveal", guesser.GuessRevealHandler(idx, sessionStore))
-	mux.Handle("/api/guess/submit/", guesser.GuessSubmitHandler(idx, sessionStore))
-	mux.Handle("/api/guess/suggest", guesser.GuessSuggestHandler(idx))
-	mux.Handle("/api/guess/ticker", guesser.GuessTickerHandler(idx))
+	mux.Handle("/api/packs", guesser.PacksHandler(library))
+
+	mux.Handle("/api/guess/start", guesser.GuessStartHandler(library, sessionStore))
+	mux.Handle("/api/guess/categories", guesser.GuessCategoriesHandler(library, sessionStore))
+	mux.Handle("/api/guess/category-meta", guesser.GuessCategoryMetaHandler(library))
+	mux.Handle("/api/guess/reveal", guesser.GuessRevealHandler(library, sessionStore))
+	mux.Handle("/api/guess/submit/", guesser.GuessSubmitHandler(library, sessionStore))
+	mux.Handle("/api/guess/suggest", guesser.GuessSuggestHandler(library))
+	mux.Handle("/api/guess/ticker", guesser.GuessTickerHandler(library))
 
 	// -----------------------------
 	// API: Dream Game Builder
 	// -----------------------------
-	mux.Handle("/api/dream/roll", guesser.DreamRollHandler(idx))
+	mux.Handle("/api/dream/roll", guesser.DreamRollHandler(library))
+	mux.Handle("/api/dream/categories", guesser.DreamCategoriesHandler(library))
+	mux.Handle("/api/dream/top", guesser.DreamFeedHandler(dreamStore, true))
+	mux.Handle("/api/dream/recent", guesser.DreamFeedHandler(dreamStore, false))
+	mux.Handle("/api/dream/sessions", guesser.DreamSessionsHandler(library, dreamStore, dreamPitches))
+	mux.Handle("/api/dream/sessions/", guesser.DreamSessionsHandler(library, dreamStore, dreamPitches))
+	mux.Handle("/api/dream/", guesser.DreamShareHandler(library, dreamStore, dreamPitches))
 
 	// -----------------------------
 	// API: Explore/Timeline
 	// -----------------------------
-	mux.Handle("/api/explore/by-year", guesser.ExploreByYearHandler(idx))
-	mux.Handle("/api/explore/by-platform", guesser.ExploreByPlatformHandler(idx))
-	mux.Handle("/api/explore/by-genre", guesser.ExploreByGenreHandler(idx))
+	mux.Handle("/api/explore/by-year", guesser.ExploreByYearHandler(library))
+	mux.Handle("/api/explore/by-platform", guesser.ExploreByPlatformHandler(library))
+	mux.Handle("/api/explore/by-genre", guesser.ExploreByGenreHandler(library))
+	mux.Handle("/api/explore/group", guesser.ExploreGroupHandler(library))
+	mux.Handle("/api/explore/timeline", guesser.TimelineHandler(library))
+
+	// -----------------------------
+	// API: Games
+	// -----------------------------
+	mux.Handle("/api/games/", guesser.GamesHandler(library))
+	mux.Handle("/api/search", guesser.SearchHandler(library))
+	mux.Handle("/api/compare", guesser.CompareHandler(library))
+	mux.Handle("/api/series", guesser.SeriesHandler(library))
+	mux.Handle("/api/series/", guesser.SeriesHandler(library))
+
+	// -----------------------------
+	// API: Dataset
+	// -----------------------------
+	mux.Handle("/api/dataset/changelog", guesser.DatasetChangelogHandler(library))
+	mux.Handle("/api/stats/dataset", guesser.DatasetStatsHandler(library))
+
+	// -----------------------------
+	// API: Admin (disabled unless TUBTUB_ADMIN_TOKEN is set)
+	// -----------------------------
+	mux.Handle("/api/admin/reload", webutil.RequireToken(adminToken, guesser.AdminReloadHandler(library)))
+	mux.Handle("/api/admin/games", webutil.RequireToken(adminToken, guesser.AdminGamesHandler(library)))
+	mux.Handle("/api/admin/games/", webutil.RequireToken(adminToken, guesser.AdminGamesHandler(library)))
+	mux.Handle("/api/admin/audit", webutil.RequireToken(adminToken, guesser.AdminAuditHandler(library)))
 
 	// -----------------------------
 	// FINAL SERVER WRAP
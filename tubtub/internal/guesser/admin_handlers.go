@@ -0,0 +1,218 @@
+package guesser
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// largest request body an admin endpoint reads; a whole game record is a few KB
+const maxAdminBody = 1 << 20
+
+type AdminReloadResponse struct {
+	Games    int            `json:"games"`
+	Warnings int            `json:"warnings"`
+	Unmapped int            `json:"unmapped"`
+	Error    string         `json:"error,omitempty"`
+	Issues   []DatasetIssue `json:"issues,omitempty"`
+}
+
+// AdminReloadHandler re-reads the ?pack= dataset on POST. A dataset that fails validation
+// is rejected and the current one stays live.
+func AdminReloadHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", 405)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxAdminBody)
+
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+
+		idx, err := cat.Reload()
+		if err != nil {
+			writeJSONStatus(w, 422, AdminReloadResponse{
+				Games:  cat.Index().Size(),
+				Error:  err.Error(),
+				Issues: rejectedIssues(err),
+			})
+			return
+		}
+
+		json.NewEncoder(w).Encode(AdminReloadResponse{
+			Games:    idx.Size(),
+			Warnings: idx.Report.WarningCount(),
+			Unmapped: len(idx.Unmapped),
+		})
+	})
+}
+
+// ----------------------------
+// ADMIN: game CRUD
+// ----------------------------
+
+type AdminGameResponse struct {
+	Game    *Game          `json:"game,omitempty"`
+	Deleted int            `json:"deleted,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Issues  []DatasetIssue `json:"issues,omitempty"`
+}
+
+// AdminGamesHandler edits the ?pack= dataset:
+//
+//	POST   /api/admin/games        create (id optional, defaults to max+1)
+//	PUT    /api/admin/games/{id}   replace the whole record
+//	PATCH  /api/admin/games/{id}   overwrite only the fields in the body
+//	DELETE /api/admin/games/{id}   remove the record
+//
+// The X-Admin-User header names the editor in the audit log.
+func AdminGamesHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+
+		id := 0
+		if rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/games"), "/"); rest != "" {
+			id, err = strconv.Atoi(rest)
+			if err != nil || id <= 0 {
+				http.Error(w, "bad game id", 400)
+				return
+			}
+		}
+
+		var action string
+		switch r.Method {
+		case http.MethodPost:
+			action = EditCreate
+		case http.MethodPut:
+			action = EditUpdate
+		case http.MethodPatch:
+			action = EditPatch
+		case http.MethodDelete:
+			action = EditDelete
+		default:
+			http.Error(w, "method not allowed", 405)
+			return
+		}
+		if action != EditCreate && id == 0 {
+			http.Error(w, "missing game id", 400)
+			return
+		}
+
+		body := map[string]json.RawMessage{}
+		if action != EditDelete {
+			r.Body = http.MaxBytesReader(w, r.Body, maxAdminBody)
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				var tooBig *http.MaxBytesError
+				if errors.As(err, &tooBig) {
+					http.Error(w, "request body too large", 413)
+					return
+				}
+				http.Error(w, "invalid json", 400)
+				return
+			}
+			if raw, ok := body["id"]; ok && action == EditCreate {
+				json.Unmarshal(raw, &id)
+			}
+		}
+
+		actor := adminActor(r)
+		game, err := cat.Edit(actor, action, id, body)
+
+		var invalid *InvalidDatasetError
+		switch {
+		case errors.Is(err, ErrGameNotFound):
+			http.Error(w, err.Error(), 404)
+			return
+		case errors.As(err, &invalid):
+			writeJSONStatus(w, 422, AdminGameResponse{Error: err.Error(), Issues: rejectedIssues(err)})
+			return
+		case err != nil && game == nil:
+			http.Error(w, err.Error(), 500)
+			return
+		case err != nil:
+			log.Printf("admin %s: %v\n", action, err)
+		}
+
+		if game != nil {
+			id = game.ID
+		}
+		log.Printf("admin %s pack=%s game=%d by %s\n", action, cat.Name(), id, actor)
+
+		status := 200
+		if action == EditCreate {
+			status = 201
+		}
+		resp := AdminGameResponse{Game: game}
+		if action == EditDelete {
+			resp.Deleted = id
+		}
+		writeJSONStatus(w, status, resp)
+	})
+}
+
+// AdminAuditHandler returns the ?pack= audit log, newest first (?limit=, default 50).
+func AdminAuditHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+
+		limit := 50
+		if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 {
+			limit = n
+		}
+
+		entries, err := cat.AuditLog(limit)
+		if err != nil {
+			http.Error(w, "cannot read audit log", 500)
+			return
+		}
+
+		json.NewEncoder(w).Encode(struct {
+			Entries []AuditEntry `json:"entries"`
+		}{Entries: entries})
+	})
+}
+
+// adminActor names who made an admin change, for logs and the audit trail.
+func adminActor(r *http.Request) string {
+	if u := strings.TrimSpace(r.Header.Get("X-Admin-User")); u != "" {
+		return u
+	}
+	return "admin"
+}
+
+// rejectedIssues pulls the error-level issues out of a validation failure
+// so the caller can see what to fix.
+func rejectedIssues(err error) []DatasetIssue {
+	var invalid *InvalidDatasetError
+	if !errors.As(err, &invalid) {
+		return nil
+	}
+	var out []DatasetIssue
+	for _, i := range invalid.Report.Issues {
+		if i.Severity == SeverityError {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func writeJSONStatus(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
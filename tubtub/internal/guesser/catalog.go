@@ -0,0 +1,120 @@
+package guesser
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Catalog owns the live Index for one dataset file and swaps it atomically on reload.
+// Handlers should call Index() once per request and use that snapshot throughout.
+type Catalog struct {
+	path      string
+	cur       atomic.Pointer[Index]
+	changelog atomic.Pointer[Changelog]
+
+	mu      sync.Mutex // serializes reloads
+	modTime time.Time
+}
+
+func OpenCatalog(path string) (*Catalog, error) {
+	c := &Catalog{path: path}
+	idx, data, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	c.swap(idx, data)
+	c.modTime = c.sourceModTime()
+	return c, nil
+}
+
+// swap makes idx live and records what changed since the last loaded version.
+func (c *Catalog) swap(idx *Index, data []byte) {
+	c.changelog.Store(updateChangelog(c.path, data, idx))
+	c.cur.Store(idx)
+}
+
+func (c *Catalog) Index() *Index {
+	return c.cur.Load()
+}
+
+// Changelog returns the pack's change history, including the live version.
+func (c *Catalog) Changelog() *Changelog {
+	return c.changelog.Load()
+}
+
+func (c *Catalog) Path() string {
+	return c.path
+}
+
+func (c *Catalog) Name() string {
+	return packNameFor(c.path)
+}
+
+// packNameFor names a pack after the directory holding its dataset.
+func packNameFor(datasetPath string) string {
+	return filepath.Base(filepath.Dir(datasetPath))
+}
+
+// Reload re-reads the dataset and only swaps it in if it loads and validates cleanly.
+// On failure the current Index stays live.
+func (c *Catalog) Reload() (*Index, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// remember the attempt even if it fails so Watch doesn't retry a broken file every tick
+	c.modTime = c.sourceModTime()
+
+	idx, data, err := c.load()
+	if err != nil {
+		log.Printf("pack %s reload failed, keeping %d games: %v\n", c.Name(), c.Index().Size(), err)
+		return nil, err
+	}
+
+	c.swap(idx, data)
+	log.Printf("pack %s reloaded: %d games\n", c.Name(), idx.Size())
+	return idx, nil
+}
+
+func (c *Catalog) load() (*Index, []byte, error) {
+	data, err := readDataset(c.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	idx, err := loadDatasetBytes(c.path, data)
+	return idx, data, err
+}
+
+// sourceModTime is the newest mtime of the dataset, its vocabulary and its manifest.
+func (c *Catalog) sourceModTime() time.Time {
+	var newest time.Time
+	for _, p := range []string{c.path, vocabularyPathFor(c.path), manifestPathFor(c.path)} {
+		if fi, err := os.Stat(p); err == nil && fi.ModTime().After(newest) {
+			newest = fi.ModTime()
+		}
+	}
+	return newest
+}
+
+// Watch polls the dataset files and reloads when they change. It returns when stop is closed.
+func (c *Catalog) Watch(interval time.Duration, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			c.mu.Lock()
+			changed := c.sourceModTime().After(c.modTime)
+			c.mu.Unlock()
+			if changed {
+				c.Reload()
+			}
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package guesser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	auditLogFile = "audit.jsonl"
+	backupDir    = "backups"
+	// backups kept per dataset; older ones are removed after each edit
+	maxBackups = 50
+)
+
+const (
+	EditCreate = "create"
+	EditUpdate = "update"
+	EditPatch  = "patch"
+	EditDelete = "delete"
+)
+
+var ErrGameNotFound = errors.New("game not found")
+
+func auditPathFor(datasetPath string) string {
+	return filepath.Join(filepath.Dir(datasetPath), auditLogFile)
+}
+
+// AuditEntry records one admin change to a pack's dataset.
+type AuditEntry struct {
+	Time   time.Time       `json:"time"`
+	Actor  string          `json:"actor"`
+	Pack   string          `json:"pack"`
+	Action string          `json:"action"`
+	GameID int             `json:"gameId"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// Edit applies one create/update/patch/delete to the dataset file.
+//
+// The edit works on the raw records so untouched games are written back byte for byte.
+// The result must validate before anything is written; the old file is copied to
+// backups/, the new one is swapped in atomically, the live Index is replaced and
+// the change is appended to audit.jsonl.
+func (c *Catalog) Edit(actor, action string, id int, body map[string]json.RawMessage) (*Game, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := readDataset(c.path)
+	if err != nil {
+		return nil, err
+	}
+	var recs []json.RawMessage
+	if err := json.Unmarshal(data, &recs); err != nil {
+		return nil, fmt.Errorf("decode dataset: %w", err)
+	}
+
+	pos := -1
+	for i, rec := range recs {
+		if recordID(rec) == id {
+			pos = i
+			break
+		}
+	}
+
+	if report := checkEditBody(id, body, c.Index().Manifest); report.HasErrors() {
+		return nil, &InvalidDatasetError{Path: c.path, Report: report}
+	}
+
+	var before, after json.RawMessage
+	switch action {
+	case EditCreate:
+		if id <= 0 {
+			id = nextRecordID(recs)
+		}
+		body["id"], _ = json.Marshal(id)
+		after = encodeRecord(body)
+		recs = append(recs, after)
+
+	case EditUpdate, EditPatch:
+		if pos < 0 {
+			return nil, ErrGameNotFound
+		}
+		before = recs[pos]
+		if action == EditPatch {
+			var cur map[string]json.RawMessage
+			if err := json.Unmarshal(before, &cur); err != nil {
+				return nil, fmt.Errorf("decode game %d: %w", id, err)
+			}
+			for k, v := range body {
+				cur[k] = v
+			}
+			body = cur
+		}
+		body["id"], _ = json.Marshal(id)
+		after = encodeRecord(body)
+		recs[pos] = after
+
+	case EditDelete:
+		if pos < 0 {
+			return nil, ErrGameNotFound
+		}
+		before = recs[pos]
+		recs = append(recs[:pos], recs[pos+1:]...)
+
+	default:
+		return nil, fmt.Errorf("unknown edit action %q", action)
+	}
+
+	out := encodeRecords(recs)
+	idx, err := loadDatasetBytes(c.path, out)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := backupFile(c.path, data); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(c.path, out); err != nil {
+		return nil, err
+	}
+	c.modTime = c.sourceModTime()
+	c.swap(idx, out)
+
+	err = appendAudit(auditPathFor(c.path), AuditEntry{
+		Time:   time.Now().UTC(),
+		Actor:  actor,
+		Pack:   c.Name(),
+		Action: action,
+		GameID: id,
+		Before: before,
+		After:  after,
+	})
+	if err != nil {
+		// the change is already live; losing the audit line shouldn't undo it
+		return idx.GameByID(id), fmt.Errorf("game %d saved but audit log failed: %w", id, err)
+	}
+
+	return idx.GameByID(id), nil
+}
+
+// AuditLog returns the newest entries of the pack's audit log, newest first.
+func (c *Catalog) AuditLog(limit int) ([]AuditEntry, error) {
+	data, err := os.ReadFile(auditPathFor(c.path))
+	if errors.Is(err, os.ErrNotExist) {
+		return []AuditEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := []AuditEntry{}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var e AuditEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			continue
+		}
+		out = append(out, e)
+	}
+
+	sort.SliceStable(out, func(a, b int) bool { return out[a].Time.After(out[b].Time) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func recordID(rec json.RawMessage) int {
+	var head struct {
+		ID int `json:"id"`
+	}
+	json.Unmarshal(rec, &head)
+	return head.ID
+}
+
+func nextRecordID(recs []json.RawMessage) int {
+	max := 0
+	for _, rec := range recs {
+		if id := recordID(rec); id > max {
+			max = id
+		}
+	}
+	return max + 1
+}
+
+// encodeRecord writes one record with its keys in Game field order, then any
+// pack-declared extras alphabetically, matching the hand-written layout of the dataset.
+func encodeRecord(m map[string]json.RawMessage) json.RawMessage {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		ia, oka := gameJSONFields[keys[a]]
+		ib, okb := gameJSONFields[keys[b]]
+		switch {
+		case oka && okb:
+			return ia < ib
+		case oka != okb:
+			return oka
+		default:
+			return keys[a] < keys[b]
+		}
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for i, k := range keys {
+		var val bytes.Buffer
+		if err := json.Compact(&val, m[k]); err != nil {
+			val.Write(m[k])
+		}
+		fmt.Fprintf(&buf, "  %q: %s", k, val.Bytes())
+		if i < len(keys)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}")
+	return buf.Bytes()
+}
+
+func encodeRecords(recs []json.RawMessage) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("[\n")
+	for i, rec := range recs {
+		buf.Write(bytes.TrimSpace(rec))
+		if i < len(recs)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+	return buf.Bytes()
+}
+
+// checkEditBody rejects body values that don't decode as their column's kind, which
+// would otherwise only fail when the whole dataset is decoded again. Unknown fields
+// are left to validation.
+func checkEditBody(id int, body map[string]json.RawMessage, manifest *PackManifest) *ValidationReport {
+	kinds := map[string]string{}
+	for _, c := range csvColumns(manifest) {
+		kinds[c.Key] = c.Kind
+	}
+	keys := make([]string, 0, len(body))
+	for k := range body {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	report := &ValidationReport{}
+	for _, k := range keys {
+		kind, ok := kinds[k]
+		if !ok {
+			continue
+		}
+		if _, err := cellValue(kind, body[k]); err != nil {
+			report.add(id, k, SeverityError, "expected %s value, got %s", kind, body[k])
+		}
+	}
+	return report
+}
+
+// backupFile copies the current dataset into backups/ before it is replaced and
+// drops all but the newest maxBackups copies.
+func backupFile(path string, data []byte) error {
+	dir := filepath.Join(filepath.Dir(path), backupDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("backup dataset: %w", err)
+	}
+	name := fmt.Sprintf("%s.%s", filepath.Base(path), time.Now().UTC().Format("20060102T150405.000000000"))
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+		return fmt.Errorf("backup dataset: %w", err)
+	}
+	if err := pruneBackups(dir, filepath.Base(path)); err != nil {
+		// the backup itself is written; a full directory isn't worth failing the edit
+		log.Printf("prune backups: %v\n", err)
+	}
+	return nil
+}
+
+func pruneBackups(dir, base string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	// the timestamp suffix sorts lexically, and ReadDir returns names in order
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), base+".") {
+			names = append(names, e.Name())
+		}
+	}
+	for _, n := range names[:max(len(names)-maxBackups, 0)] {
+		if err := os.Remove(filepath.Join(dir, n)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFileAtomic writes to a temp file in the same directory and renames it over path.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write dataset: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write dataset: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write dataset: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write dataset: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("write dataset: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write dataset: %w", err)
+	}
+	return nil
+}
+
+func appendAudit(path string, e AuditEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,121 @@
+package guesser
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writePack puts a dataset (and an optional pack.json) in a fresh pack directory
+// and returns the dataset's path.
+func writePack(t *testing.T, dataset, manifest string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "games")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, packDatasetFile)
+	if err := os.WriteFile(path, []byte(dataset), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if manifest != "" {
+		if err := os.WriteFile(filepath.Join(dir, "pack.json"), []byte(manifest), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+const editFixture = `[
+{"id": 1, "name": "Alpha", "year": 2010, "platforms": ["PC"]},
+{"id": 2, "name": "Beta", "year": 2012, "platforms": ["PC"]}
+]`
+
+func editBody(t *testing.T, s string) map[string]json.RawMessage {
+	t.Helper()
+	body := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(s), &body); err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestCatalogEditErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		action    string
+		id        int
+		body      string
+		notFound  bool
+		badFields []string // fields the *InvalidDatasetError must name
+	}{
+		{name: "valid patch", action: EditPatch, id: 1, body: `{"year": 2011}`},
+		{name: "string for int", action: EditPatch, id: 1, body: `{"year": "abc"}`, badFields: []string{"year"}},
+		{name: "number for string", action: EditPatch, id: 1, body: `{"name": 5}`, badFields: []string{"name"}},
+		{name: "string for list", action: EditPatch, id: 1, body: `{"platforms": "PC"}`, badFields: []string{"platforms"}},
+		{name: "two bad fields", action: EditUpdate, id: 1, body: `{"name": "Alpha", "year": "x", "sub_genres": 3}`, badFields: []string{"sub_genres", "year"}},
+		{name: "unknown field", action: EditPatch, id: 1, body: `{"colour": "red"}`, badFields: []string{"colour"}},
+		{name: "create without year", action: EditCreate, body: `{"name": "Gamma"}`, badFields: []string{"year"}},
+		{name: "create", action: EditCreate, body: `{"name": "Gamma", "year": 2020}`},
+		{name: "patch missing game", action: EditPatch, id: 99, body: `{"year": 2011}`, notFound: true},
+		{name: "delete missing game", action: EditDelete, id: 99, notFound: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := OpenCatalog(writePack(t, editFixture, ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var body map[string]json.RawMessage
+			if tt.body != "" {
+				body = editBody(t, tt.body)
+			}
+
+			g, err := c.Edit("test", tt.action, tt.id, body)
+
+			var invalid *InvalidDatasetError
+			switch {
+			case tt.notFound:
+				if !errors.Is(err, ErrGameNotFound) {
+					t.Fatalf("err = %v, want ErrGameNotFound", err)
+				}
+			case tt.badFields != nil:
+				if !errors.As(err, &invalid) {
+					t.Fatalf("err = %v, want *InvalidDatasetError", err)
+				}
+				var got []string
+				for _, i := range rejectedIssues(err) {
+					got = append(got, i.Field)
+				}
+				if !equalStrings(got, tt.badFields) {
+					t.Fatalf("issues name %v, want %v", got, tt.badFields)
+				}
+				if n := c.Index().Size(); n != 2 {
+					t.Fatalf("rejected edit changed the dataset: %d games", n)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				if g == nil {
+					t.Fatal("no game returned")
+				}
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -2,75 +2,95 @@ package guesser
 
 import "math/rand"
 
-// all possible category fields
-var allCategories = []string{
-	"primary_genre",
-	"sub_genres",
-	"platforms",
-	"series",
-	"protagonist_type",
-	"protagonist_identity",
-	"protagonist_gender",
-	"protagonist_role",
-	"world_type",
-	"world_setting",
-	"world_origin",
-	"time_period",
-	"environment_type",
-	"world_tone",
-	"story_presence",
-	"story_structure",
-	"story_themes",
-	"dialogue_type",
-	"choices_impact",
-	"narrative_perspective",
-	"combat_style",
-	"combat_pacing",
-	"combat_complexity",
-	"movement_type",
-	"enemy_types",
-	"camera_view",
-	"camera_behavior",
-	"visual_style",
-	"color_palette",
-	"game_structure",
-	"progression_type",
-	"crafting_system",
-	"loot_system",
-	"economic_system",
-	"puzzle_presence",
-	"multiplayer_presence",
-	"multiplayer_type",
-	"online_requirement",
-	"coop_scale",
-	"pvp_scale",
-	"overall_tone",
-	"player_emotion",
-	"vibe_tags",
-	"difficulty_style",
-	"challenge_type",
-	"average_playtime",
-	"pace",
-	"immersion_type",
-	"reward_style",
-	"violence_level",
-	"maturity_level",
-	"major_themes",
-	"special_mechanics",
-	"iconic_features",
-	"world_features",
-	"year",
+// Sections group categories for side-by-side views, in display order.
+const (
+	SectionBasics       = "Basics"
+	SectionProtagonist  = "Protagonist"
+	SectionWorld        = "World"
+	SectionStory        = "Story"
+	SectionCombat       = "Combat"
+	SectionPresentation = "Presentation"
+	SectionSystems      = "Systems"
+	SectionMultiplayer  = "Multiplayer"
+	SectionFeel         = "Feel"
+	SectionChallenge    = "Challenge"
+	SectionContent      = "Content"
+	SectionFeatures     = "Features"
+	// pack-declared categories that don't pick a section
+	SectionOther = "Other"
+)
+
+// builtinCategories declares every clue category backed by a Game field.
+// Kind and ListCap are filled from the struct in init; Strength rates how much a
+// clue gives away (1 = vague, 3 = close to naming the game).
+var builtinCategories = []CategoryDef{
+	{Key: "primary_genre", Label: "Primary Genre", Strength: 2, Section: SectionBasics},
+	{Key: "sub_genres", Label: "Sub-genres", Strength: 2, Section: SectionBasics},
+	{Key: "platforms", Label: "Platforms", Strength: 1, Section: SectionBasics},
+	{Key: "series", Label: "Series", Strength: 3, Section: SectionBasics},
+	{Key: "protagonist_type", Label: "Protagonist Type", Strength: 1, Section: SectionProtagonist},
+	{Key: "protagonist_identity", Label: "Protagonist Identity", Strength: 3, Section: SectionProtagonist},
+	{Key: "protagonist_gender", Label: "Protagonist Gender", Strength: 1, Section: SectionProtagonist},
+	{Key: "protagonist_role", Label: "Protagonist Role", Strength: 2, Section: SectionProtagonist},
+	{Key: "world_type", Label: "World Type", Strength: 2, Section: SectionWorld},
+	{Key: "world_setting", Label: "World Setting", Strength: 2, Section: SectionWorld},
+	{Key: "world_origin", Label: "World Origin", Strength: 3, Section: SectionWorld},
+	{Key: "time_period", Label: "Time Period", Strength: 2, Section: SectionWorld},
+	{Key: "environment_type", Label: "Environment", Strength: 2, Section: SectionWorld},
+	{Key: "world_tone", Label: "World Tone", Strength: 1, Section: SectionWorld},
+	{Key: "story_presence", Label: "Story Presence", Strength: 1, Section: SectionStory},
+	{Key: "story_structure", Label: "Story Structure", Strength: 2, Section: SectionStory},
+	{Key: "story_themes", Label: "Story Themes", Strength: 2, Section: SectionStory},
+	{Key: "dialogue_type", Label: "Dialogue", Strength: 1, Section: SectionStory},
+	{Key: "choices_impact", Label: "Choices Impact", Strength: 1, Section: SectionStory},
+	{Key: "narrative_perspective", Label: "Narrative Perspective", Strength: 1, Section: SectionStory},
+	{Key: "combat_style", Label: "Combat Style", Strength: 2, Section: SectionCombat},
+	{Key: "combat_pacing", Label: "Combat Pacing", Strength: 1, Section: SectionCombat},
+	{Key: "combat_complexity", Label: "Combat Complexity", Strength: 1, Section: SectionCombat},
+	{Key: "movement_type", Label: "Movement", Strength: 2, Section: SectionCombat},
+	{Key: "enemy_types", Label: "Enemy Types", Strength: 2, Section: SectionCombat},
+	{Key: "camera_view", Label: "Camera View", Strength: 1, Section: SectionPresentation},
+	{Key: "camera_behavior", Label: "Camera Behavior", Strength: 1, Section: SectionPresentation},
+	{Key: "visual_style", Label: "Visual Style", Strength: 1, Section: SectionPresentation},
+	{Key: "color_palette", Label: "Color Palette", Strength: 1, Section: SectionPresentation},
+	{Key: "game_structure", Label: "Game Structure", Strength: 2, Section: SectionSystems},
+	{Key: "progression_type", Label: "Progression", Strength: 2, Section: SectionSystems},
+	{Key: "crafting_system", Label: "Crafting", Strength: 1, Section: SectionSystems},
+	{Key: "loot_system", Label: "Loot", Strength: 1, Section: SectionSystems},
+	{Key: "economic_system", Label: "Economy", Strength: 2, Section: SectionSystems},
+	{Key: "puzzle_presence", Label: "Puzzles", Strength: 1, Section: SectionSystems},
+	{Key: "multiplayer_presence", Label: "Multiplayer Presence", Strength: 1, Section: SectionMultiplayer},
+	{Key: "multiplayer_type", Label: "Multiplayer Type", Strength: 1, Section: SectionMultiplayer},
+	{Key: "online_requirement", Label: "Online Requirement", Strength: 1, Section: SectionMultiplayer},
+	{Key: "coop_scale", Label: "Co-op Scale", Strength: 1, Section: SectionMultiplayer},
+	{Key: "pvp_scale", Label: "PvP Scale", Strength: 1, Section: SectionMultiplayer},
+	{Key: "overall_tone", Label: "Overall Tone", Strength: 1, Section: SectionFeel},
+	{Key: "player_emotion", Label: "Player Emotion", Strength: 1, Section: SectionFeel},
+	{Key: "vibe_tags", Label: "Vibe Tags", Strength: 2, Section: SectionFeel},
+	{Key: "difficulty_style", Label: "Difficulty Style", Strength: 1, Section: SectionChallenge},
+	{Key: "challenge_type", Label: "Challenge Type", Strength: 1, Section: SectionChallenge},
+	{Key: "average_playtime", Label: "Average Playtime", Strength: 1, Section: SectionChallenge},
+	{Key: "pace", Label: "Pace", Strength: 1, Section: SectionFeel},
+	{Key: "immersion_type", Label: "Immersion", Strength: 1, Section: SectionFeel},
+	{Key: "reward_style", Label: "Reward Style", Strength: 1, Section: SectionFeel},
+	{Key: "violence_level", Label: "Violence Level", Strength: 1, Section: SectionContent},
+	{Key: "maturity_level", Label: "Maturity Rating", Strength: 1, Section: SectionContent},
+	{Key: "major_themes", Label: "Major Themes", Strength: 2, Section: SectionStory},
+	{Key: "special_mechanics", Label: "Special Mechanics", Strength: 3, Section: SectionFeatures},
+	{Key: "iconic_features", Label: "Iconic Features", Strength: 3, Section: SectionFeatures},
+	{Key: "world_features", Label: "World Features", Strength: 3, Section: SectionFeatures},
+	{Key: "year", Label: "Release Year", Strength: 2, Section: SectionBasics},
 }
 
 // return ALL currently available unused categories in random order
-func RandomCategories(g *Game, used map[string]bool) []string {
+func RandomCategories(reg *CategoryRegistry, g *Game, used map[string]bool) []string {
 	var available []string
 
-	for _, c := range allCategories {
+	for _, c := range reg.Keys() {
 		if used[c] {
 			continue
 		}
-		if CategoryHasValue(g, c) {
+		if reg.HasValue(g, c) {
 			available = append(available, c)
 		}
 	}
@@ -0,0 +1,258 @@
+package guesser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// default cap on how many list entries a clue shows
+const defaultListCap = 10
+
+// CategoryDef describes one clue category: where its value comes from and how it is shown.
+type CategoryDef struct {
+	Key      string `json:"key"`
+	Label    string `json:"label"`
+	Kind     string `json:"kind"`
+	ListCap  int    `json:"listCap,omitempty"`
+	Strength int    `json:"strength"`
+	Section  string `json:"section,omitempty"`
+}
+
+// UnmarshalJSON lets pack.json list a built-in category by key alone.
+func (d *CategoryDef) UnmarshalJSON(data []byte) error {
+	var key string
+	if err := json.Unmarshal(data, &key); err == nil {
+		*d = CategoryDef{Key: key}
+		return nil
+	}
+	type plain CategoryDef
+	return json.Unmarshal(data, (*plain)(d))
+}
+
+// value reads the category from a record: Game fields through their json tag,
+// anything else from Extra. Placeholders and empty values come back as nil.
+func (d CategoryDef) value(g *Game) interface{} {
+	var raw interface{}
+	if fi, ok := gameJSONFields[d.Key]; ok {
+		raw = reflect.ValueOf(g).Elem().Field(fi).Interface()
+	} else {
+		raw = g.Extra[d.Key]
+	}
+
+	switch v := raw.(type) {
+	case string:
+		if v := CleanString(v); v != "" {
+			return v
+		}
+	case []string:
+		if l := CleanList(v, d.ListCap); l != nil {
+			return l
+		}
+	case int:
+		if v > 0 {
+			return v
+		}
+	}
+	return nil
+}
+
+// kindOfField maps a Game field's Go type to a category kind.
+func kindOfField(key string) string {
+	fi, ok := gameJSONFields[key]
+	if !ok {
+		return ""
+	}
+	switch reflect.TypeOf(Game{}).Field(fi).Type.Kind() {
+	case reflect.Slice:
+		return KindList
+	case reflect.Int:
+		return KindInt
+	default:
+		return KindString
+	}
+}
+
+func humanizeKey(key string) string {
+	words := strings.Fields(strings.ReplaceAll(key, "_", " "))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// ----------------------------
+// Registry
+// ----------------------------
+
+type CategoryRegistry struct {
+	defs  []CategoryDef
+	byKey map[string]int
+}
+
+func newCategoryRegistry(defs []CategoryDef) *CategoryRegistry {
+	r := &CategoryRegistry{defs: defs, byKey: map[string]int{}}
+	for i, d := range defs {
+		r.byKey[d.Key] = i
+	}
+	return r
+}
+
+var builtinByKey = map[string]CategoryDef{}
+
+// defaultRegistry is used for the built-in game fields and by ExtractCategoryValue.
+var defaultRegistry = func() *CategoryRegistry {
+	for i := range builtinCategories {
+		d := &builtinCategories[i]
+		d.Kind = kindOfField(d.Key)
+		if d.Kind == KindList {
+			d.ListCap = defaultListCap
+		}
+		builtinByKey[d.Key] = *d
+	}
+	return newCategoryRegistry(builtinCategories)
+}()
+
+// resolveCategoryDefs fills in a pack's category list: built-in keys inherit the
+// built-in definition (with any overrides), custom keys must declare a kind.
+func resolveCategoryDefs(in []CategoryDef) ([]CategoryDef, error) {
+	if len(in) == 0 {
+		return builtinCategories, nil
+	}
+
+	out := make([]CategoryDef, 0, len(in))
+	seen := map[string]bool{}
+	for _, d := range in {
+		if d.Key == "" {
+			return nil, fmt.Errorf("category without key")
+		}
+		if seen[d.Key] {
+			return nil, fmt.Errorf("category %q listed twice", d.Key)
+		}
+		seen[d.Key] = true
+
+		if base, ok := builtinByKey[d.Key]; ok {
+			if d.Kind != "" && d.Kind != base.Kind {
+				return nil, fmt.Errorf("category %q is a %s field, not %s", d.Key, base.Kind, d.Kind)
+			}
+			if d.Label != "" {
+				base.Label = d.Label
+			}
+			if d.ListCap > 0 && base.Kind == KindList {
+				base.ListCap = d.ListCap
+			}
+			if d.Strength > 0 {
+				base.Strength = d.Strength
+			}
+			if d.Section != "" {
+				base.Section = d.Section
+			}
+			out = append(out, base)
+			continue
+		}
+
+		switch d.Kind {
+		case KindString, KindInt:
+			d.ListCap = 0
+		case KindList:
+			if d.ListCap <= 0 {
+				d.ListCap = defaultListCap
+			}
+		default:
+			return nil, fmt.Errorf("category %q needs a kind (string, list or int)", d.Key)
+		}
+		if d.Label == "" {
+			d.Label = humanizeKey(d.Key)
+		}
+		if d.Strength <= 0 {
+			d.Strength = 2
+		}
+		if d.Section == "" {
+			d.Section = SectionOther
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+func (r *CategoryRegistry) Defs() []CategoryDef {
+	return r.defs
+}
+
+func (r *CategoryRegistry) Keys() []string {
+	keys := make([]string, len(r.defs))
+	for i, d := range r.defs {
+		keys[i] = d.Key
+	}
+	return keys
+}
+
+func (r *CategoryRegistry) Def(key string) (CategoryDef, bool) {
+	i, ok := r.byKey[key]
+	if !ok {
+		return CategoryDef{}, false
+	}
+	return r.defs[i], true
+}
+
+// Value extracts a category's clean value, or nil if the record has none
+// or the category isn't part of this registry.
+func (r *CategoryRegistry) Value(g *Game, key string) interface{} {
+	if g == nil {
+		return nil
+	}
+	d, ok := r.Def(key)
+	if !ok {
+		return nil
+	}
+	return d.value(g)
+}
+
+func (r *CategoryRegistry) HasValue(g *Game, key string) bool {
+	return r.Value(g, key) != nil
+}
+
+// Values returns every clean value of a category as strings, ignoring the list cap.
+// Used for grouping and counting rather than for clues.
+func (r *CategoryRegistry) Values(g *Game, key string) []string {
+	d, ok := r.Def(key)
+	if !ok || g == nil {
+		return nil
+	}
+	d.ListCap = 0
+
+	switch v := d.value(g).(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case int:
+		return []string{strconv.Itoa(v)}
+	}
+	return nil
+}
+
+// ----------------------------
+// API: category metadata
+// ----------------------------
+
+// GuessCategoryMetaHandler lists the ?pack= categories with their labels so the
+// frontend doesn't need its own label table.
+func GuessCategoryMetaHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+
+		json.NewEncoder(w).Encode(struct {
+			Categories []CategoryDef `json:"categories"`
+		}{
+			Categories: cat.Index().Registry.Defs(),
+		})
+	})
+}
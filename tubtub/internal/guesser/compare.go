@@ -0,0 +1,250 @@
+package guesser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// games one /api/compare request may line up
+	maxCompareGames = 6
+	// values named in each game's "unique" summary
+	compareUniqueValues = 5
+)
+
+// CompareCell is one game's value(s) for a row. Shared lists the values at least
+// one other compared game also has, for highlighting; an explicit "None" is never shared.
+type CompareCell struct {
+	Values []string `json:"values"`
+	Shared []string `json:"shared,omitempty"`
+}
+
+// CompareRow is one category across the compared games, in request order.
+type CompareRow struct {
+	Field   string        `json:"field"`
+	Label   string        `json:"label"`
+	Cells   []CompareCell `json:"cells"`
+	AllSame bool          `json:"allSame"`
+}
+
+// CompareSection groups rows. Score is the mean pairwise similarity over the
+// section's fields both games fill in; nil when no pair could be compared.
+type CompareSection struct {
+	Section string       `json:"section"`
+	Score   *float64     `json:"score"`
+	Rows    []CompareRow `json:"rows"`
+}
+
+// CompareUnique is what only one of the compared games has.
+type CompareUnique struct {
+	GameSummary
+	Summary string        `json:"summary"`
+	Unique  []SharedField `json:"unique"`
+}
+
+type CompareResponse struct {
+	Games    []GameSummary    `json:"games"`
+	Score    *float64         `json:"score"`
+	Sections []CompareSection `json:"sections"`
+	Unique   []CompareUnique  `json:"unique"`
+}
+
+// fieldSimilarity scores one field for a pair: Jaccard for lists, equality otherwise.
+func fieldSimilarity(d CategoryDef, a, b []string) float64 {
+	if d.Kind == KindList {
+		return jaccard(a, b)
+	}
+	if strings.EqualFold(a[0], b[0]) {
+		return 1
+	}
+	return 0
+}
+
+func roundScore(num, den float64) *float64 {
+	if den == 0 {
+		return nil
+	}
+	s := float64(int(num/den*1000+0.5)) / 1000
+	return &s
+}
+
+// CompareGames lines the games up field by field.
+func CompareGames(reg *CategoryRegistry, games []*Game) *CompareResponse {
+	out := &CompareResponse{Games: []GameSummary{}, Sections: []CompareSection{}, Unique: []CompareUnique{}}
+	for _, g := range games {
+		out.Games = append(out.Games, summarize(g))
+	}
+
+	// values[field][game], uncapped: the pack's list cap is for clues
+	values := map[string][][]string{}
+	for _, d := range reg.Defs() {
+		for _, g := range games {
+			values[d.Key] = append(values[d.Key], reg.Values(g, d.Key))
+		}
+	}
+
+	sectionAt := map[string]int{}
+	totalNum, totalDen := 0.0, 0.0
+	sectionNum := map[string]float64{}
+	sectionDen := map[string]float64{}
+	for _, d := range reg.Defs() {
+		vals := values[d.Key]
+		row := CompareRow{Field: d.Key, Label: d.Label, Cells: make([]CompareCell, len(games)), AllSame: true}
+
+		// how many games have each value
+		holders := map[string]int{}
+		for _, vs := range vals {
+			for k := range lowerSet(vs) {
+				holders[k]++
+			}
+		}
+		for i, vs := range vals {
+			row.Cells[i].Values = vs
+			if vs == nil {
+				row.Cells[i].Values = []string{}
+			}
+			// a shared "None" isn't something the games have in common
+			if len(vs) == 0 || isAbsent(vs) {
+				row.AllSame = false
+				continue
+			}
+			for _, v := range vs {
+				n := holders[strings.ToLower(v)]
+				if n > 1 {
+					row.Cells[i].Shared = append(row.Cells[i].Shared, v)
+				}
+				if n < len(games) {
+					row.AllSame = false
+				}
+			}
+		}
+
+		for i := range vals {
+			for j := i + 1; j < len(vals); j++ {
+				if len(vals[i]) == 0 || len(vals[j]) == 0 {
+					continue
+				}
+				// weighted like similarity(): matching "None"s count for little
+				w := similarityWeight(d.Key)
+				if isAbsent(vals[i]) && isAbsent(vals[j]) {
+					w *= sharedAbsenceWeight
+				}
+				s := fieldSimilarity(d, vals[i], vals[j])
+				sectionNum[d.Section] += w * s
+				sectionDen[d.Section] += w
+				totalNum += w * s
+				totalDen += w
+			}
+		}
+
+		at, ok := sectionAt[d.Section]
+		if !ok {
+			at = len(out.Sections)
+			sectionAt[d.Section] = at
+			out.Sections = append(out.Sections, CompareSection{Section: d.Section})
+		}
+		out.Sections[at].Rows = append(out.Sections[at].Rows, row)
+	}
+	for i := range out.Sections {
+		sec := out.Sections[i].Section
+		out.Sections[i].Score = roundScore(sectionNum[sec], sectionDen[sec])
+	}
+	out.Score = roundScore(totalNum, totalDen)
+
+	// unique: values no other compared game has, heaviest categories first
+	for i, g := range games {
+		u := CompareUnique{GameSummary: summarize(g), Unique: []SharedField{}}
+		for _, d := range reg.Defs() {
+			mine := values[d.Key][i]
+			if len(mine) == 0 || isAbsent(mine) || d.Kind == KindInt {
+				continue
+			}
+			others := map[string]bool{}
+			for j, vs := range values[d.Key] {
+				if j != i {
+					for k := range lowerSet(vs) {
+						others[k] = true
+					}
+				}
+			}
+			var only []string
+			for _, v := range mine {
+				if !others[strings.ToLower(v)] {
+					only = append(only, v)
+				}
+			}
+			if len(only) > 0 {
+				u.Unique = append(u.Unique, SharedField{Field: d.Key, Label: d.Label, Values: only})
+			}
+		}
+		sort.SliceStable(u.Unique, func(x, y int) bool {
+			return similarityWeight(u.Unique[x].Field) > similarityWeight(u.Unique[y].Field)
+		})
+		u.Summary = uniqueSummary(u.Unique)
+		out.Unique = append(out.Unique, u)
+	}
+	return out
+}
+
+func uniqueSummary(unique []SharedField) string {
+	var vals []string
+	for _, f := range unique {
+		for _, v := range f.Values {
+			if len(vals) < compareUniqueValues {
+				vals = append(vals, strings.ToLower(v))
+			}
+		}
+	}
+	if len(vals) == 0 {
+		return ""
+	}
+	return "only one with: " + strings.Join(vals, ", ")
+}
+
+// ----------------------------
+// API: compare
+// ----------------------------
+
+// CompareHandler serves /api/compare?ids=1,2,3 for the ?pack= dataset: every
+// category side by side for 2 to 6 games, with shared values marked, a similarity
+// score per section and what sets each game apart.
+func CompareHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+		idx := cat.Index()
+
+		var games []*Game
+		seen := map[int]bool{}
+		for _, p := range splitParam(r.URL.Query()["ids"]) {
+			id, err := strconv.Atoi(p)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("bad game id %q", p), 400)
+				return
+			}
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			g := idx.GameByID(id)
+			if g == nil {
+				http.Error(w, fmt.Sprintf("game %d not found", id), 404)
+				return
+			}
+			games = append(games, g)
+		}
+		if len(games) < 2 || len(games) > maxCompareGames {
+			http.Error(w, fmt.Sprintf("compare needs 2 to %d distinct ids", maxCompareGames), 400)
+			return
+		}
+
+		json.NewEncoder(w).Encode(CompareGames(idx.Registry, games))
+	})
+}
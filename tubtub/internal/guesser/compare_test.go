@@ -0,0 +1,65 @@
+package guesser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCompareUsesUncappedLists(t *testing.T) {
+	var subs, tags []string
+	for i := 1; i <= 12; i++ {
+		subs = append(subs, fmt.Sprintf("%q", fmt.Sprintf("Sub %d", i)))
+	}
+	for i := 1; i <= 5; i++ {
+		tags = append(tags, fmt.Sprintf("%q", fmt.Sprintf("Tag %d", i)))
+	}
+	dataset := fmt.Sprintf(`[
+{"id": 1, "name": "Alpha", "year": 2010, "sub_genres": [%s], "tags": [%s]},
+{"id": 2, "name": "Beta", "year": 2012, "sub_genres": ["Sub 11", "Sub 12"], "tags": ["Tag 5"]}
+]`, strings.Join(subs, ", "), strings.Join(tags, ", "))
+	manifest := `{
+  "categories": ["year", {"key": "sub_genres", "listCap": 2}, {"key": "tags", "kind": "list", "listCap": 3}],
+  "fields": {"tags": "list"}
+}`
+	c, err := OpenCatalog(writePack(t, dataset, manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx := c.Index()
+
+	res := CompareGames(idx.Registry, []*Game{idx.GameByID(1), idx.GameByID(2)})
+	rows := map[string]CompareRow{}
+	for _, sec := range res.Sections {
+		for _, r := range sec.Rows {
+			rows[r.Field] = r
+		}
+	}
+
+	tests := []struct {
+		field  string
+		values int
+		shared []string
+	}{
+		{field: "sub_genres", values: 12, shared: []string{"Sub 11", "Sub 12"}},
+		{field: "tags", values: 5, shared: []string{"Tag 5"}},
+	}
+	for _, tt := range tests {
+		row, ok := rows[tt.field]
+		if !ok {
+			t.Fatalf("no %s row", tt.field)
+		}
+		cell := row.Cells[0]
+		if len(cell.Values) != tt.values {
+			t.Errorf("%s: %d values, want %d (uncapped)", tt.field, len(cell.Values), tt.values)
+		}
+		if !equalStrings(cell.Shared, tt.shared) {
+			t.Errorf("%s: shared %v, want %v", tt.field, cell.Shared, tt.shared)
+		}
+	}
+
+	// year differs; sub_genres 2/12 and tags 1/5 by Jaccard, weighted by category
+	if res.Score == nil || *res.Score <= 0 || *res.Score >= 0.5 {
+		t.Fatalf("score = %v, want low but non-zero", res.Score)
+	}
+}
@@ -0,0 +1,387 @@
+package guesser
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultListDelimiter separates list entries inside a single CSV cell.
+const DefaultListDelimiter = "|"
+
+type csvColumn struct {
+	Key  string
+	Kind string
+}
+
+// csvColumns lists the dataset's columns: Game fields in struct order, then the
+// pack's custom fields alphabetically.
+func csvColumns(manifest *PackManifest) []csvColumn {
+	var cols []csvColumn
+	t := reflect.TypeOf(Game{})
+	for i := 0; i < t.NumField(); i++ {
+		key := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+		cols = append(cols, csvColumn{Key: key, Kind: kindOfField(key)})
+	}
+
+	var extra []string
+	for k := range manifest.Fields {
+		extra = append(extra, k)
+	}
+	sort.Strings(extra)
+	for _, k := range extra {
+		cols = append(cols, csvColumn{Key: k, Kind: manifest.Fields[k]})
+	}
+	return cols
+}
+
+// cellValue is one field in comparable form: string, []string or int.
+func cellValue(kind string, raw json.RawMessage) (interface{}, error) {
+	switch kind {
+	case KindList:
+		var l []string
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &l); err != nil {
+				return nil, err
+			}
+		}
+		out := []string{}
+		for _, v := range l {
+			if v = strings.TrimSpace(v); v != "" {
+				out = append(out, v)
+			}
+		}
+		return out, nil
+	case KindInt:
+		var n int
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &n); err != nil {
+				return nil, err
+			}
+		}
+		return n, nil
+	default:
+		var s string
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return nil, err
+			}
+		}
+		return s, nil
+	}
+}
+
+func parseCell(kind, cell, delim string) (interface{}, error) {
+	switch kind {
+	case KindList:
+		out := []string{}
+		if strings.TrimSpace(cell) == "" {
+			return out, nil
+		}
+		for _, v := range strings.Split(cell, delim) {
+			if v = strings.TrimSpace(v); v != "" {
+				out = append(out, v)
+			}
+		}
+		return out, nil
+	case KindInt:
+		if strings.TrimSpace(cell) == "" {
+			return 0, nil
+		}
+		return strconv.Atoi(strings.TrimSpace(cell))
+	default:
+		return cell, nil
+	}
+}
+
+// ----------------------------
+// Export
+// ----------------------------
+
+// ExportCSV writes the dataset at path as CSV, one row per record, straight from the
+// file (no vocabulary normalization) so an import of the output changes nothing.
+func ExportCSV(path string, w io.Writer, delim string) error {
+	manifest, recs, err := readRawRecords(path)
+	if err != nil {
+		return err
+	}
+	cols := csvColumns(manifest)
+
+	cw := csv.NewWriter(w)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Key
+	}
+	cw.Write(header)
+
+	for _, rec := range recs {
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(rec, &m); err != nil {
+			return fmt.Errorf("decode record: %w", err)
+		}
+
+		row := make([]string, len(cols))
+		for i, c := range cols {
+			v, err := cellValue(c.Kind, m[c.Key])
+			if err != nil {
+				return fmt.Errorf("game %d field %s: %w", recordID(rec), c.Key, err)
+			}
+			switch v := v.(type) {
+			case []string:
+				for _, item := range v {
+					if strings.Contains(item, delim) {
+						return fmt.Errorf("game %d field %s: %q contains the list delimiter %q", recordID(rec), c.Key, item, delim)
+					}
+				}
+				row[i] = strings.Join(v, delim)
+			case int:
+				if v != 0 {
+					row[i] = strconv.Itoa(v)
+				}
+			case string:
+				row[i] = v
+			}
+		}
+		cw.Write(row)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ----------------------------
+// Import
+// ----------------------------
+
+// ImportChange is one record an import would add or modify.
+type ImportChange struct {
+	GameID int      `json:"gameId"`
+	Name   string   `json:"name"`
+	Added  bool     `json:"added"`
+	Fields []string `json:"fields,omitempty"`
+
+	before, after json.RawMessage
+}
+
+// ImportPlan is the preview of a CSV import; Apply writes it.
+type ImportPlan struct {
+	Changes   []ImportChange
+	Unchanged int
+	// ids in the dataset with no CSV row; they are left alone
+	NotInCSV []int
+
+	path string
+	data []byte
+	recs []json.RawMessage
+}
+
+// PlanCSVImport compares a CSV against the dataset at path. Only columns present in
+// the CSV are compared, rows without an id become new records, and records whose
+// values match keep their original bytes.
+func PlanCSVImport(path string, r io.Reader, delim string) (*ImportPlan, error) {
+	manifest, recs, err := readRawRecords(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := readDataset(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	kinds := map[string]string{}
+	for _, c := range csvColumns(manifest) {
+		kinds[c.Key] = c.Kind
+	}
+	idCol := -1
+	for i, h := range header {
+		h = strings.TrimSpace(h)
+		header[i] = h
+		if _, ok := kinds[h]; !ok {
+			return nil, fmt.Errorf("csv column %q is not a dataset field", h)
+		}
+		if h == "id" {
+			idCol = i
+		}
+	}
+	if idCol < 0 {
+		return nil, fmt.Errorf("csv has no id column")
+	}
+
+	byID := map[int]int{}
+	for i, rec := range recs {
+		byID[recordID(rec)] = i
+	}
+
+	plan := &ImportPlan{path: path, data: data}
+	seen := map[int]bool{}
+	nextID := nextRecordID(recs)
+
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv line %d: %w", line, err)
+		}
+
+		vals := map[string]interface{}{}
+		for i, h := range header {
+			v, err := parseCell(kinds[h], row[i], delim)
+			if err != nil {
+				return nil, fmt.Errorf("csv line %d column %s: %w", line, h, err)
+			}
+			vals[h] = v
+		}
+
+		id := vals["id"].(int)
+		if id == 0 {
+			id = nextID
+			nextID++
+			vals["id"] = id
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("csv line %d: id %d appears twice", line, id)
+		}
+		seen[id] = true
+		name, _ := vals["name"].(string)
+
+		pos, exists := byID[id]
+		if !exists {
+			m := map[string]json.RawMessage{}
+			for k, v := range vals {
+				if !isZeroCell(v) || k == "id" {
+					m[k], _ = json.Marshal(v)
+				}
+			}
+			rec := encodeRecord(m)
+			recs = append(recs, rec)
+			plan.Changes = append(plan.Changes, ImportChange{GameID: id, Name: name, Added: true, after: rec})
+			continue
+		}
+
+		var cur map[string]json.RawMessage
+		if err := json.Unmarshal(recs[pos], &cur); err != nil {
+			return nil, fmt.Errorf("decode game %d: %w", id, err)
+		}
+		if name == "" {
+			json.Unmarshal(cur["name"], &name)
+		}
+
+		var changed []string
+		for _, h := range header {
+			old, err := cellValue(kinds[h], cur[h])
+			if err != nil {
+				return nil, fmt.Errorf("game %d field %s: %w", id, h, err)
+			}
+			if reflect.DeepEqual(old, vals[h]) {
+				continue
+			}
+			changed = append(changed, h)
+			cur[h], _ = json.Marshal(vals[h])
+		}
+
+		if len(changed) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		before := recs[pos]
+		recs[pos] = encodeRecord(cur)
+		plan.Changes = append(plan.Changes, ImportChange{GameID: id, Name: name, Fields: changed, before: before, after: recs[pos]})
+	}
+
+	for id := range byID {
+		if !seen[id] {
+			plan.NotInCSV = append(plan.NotInCSV, id)
+		}
+	}
+	sort.Ints(plan.NotInCSV)
+
+	plan.recs = recs
+	return plan, nil
+}
+
+func isZeroCell(v interface{}) bool {
+	switch v := v.(type) {
+	case string:
+		return v == ""
+	case []string:
+		return len(v) == 0
+	case int:
+		return v == 0
+	}
+	return true
+}
+
+// Apply validates the imported dataset, backs up the old file, writes the new one
+// atomically and appends one audit entry per change.
+func (p *ImportPlan) Apply(actor string) error {
+	if len(p.Changes) == 0 {
+		return nil
+	}
+
+	out := encodeRecords(p.recs)
+	if _, err := loadDatasetBytes(p.path, out); err != nil {
+		return err
+	}
+	if err := backupFile(p.path, p.data); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(p.path, out); err != nil {
+		return err
+	}
+
+	auditPath := auditPathFor(p.path)
+	pack := packNameFor(p.path)
+	now := time.Now().UTC()
+	for _, c := range p.Changes {
+		action := EditUpdate
+		if c.Added {
+			action = EditCreate
+		}
+		if err := appendAudit(auditPath, AuditEntry{
+			Time:   now,
+			Actor:  actor,
+			Pack:   pack,
+			Action: action,
+			GameID: c.GameID,
+			Before: c.before,
+			After:  c.after,
+		}); err != nil {
+			return fmt.Errorf("dataset written but audit log failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// readRawRecords returns the pack manifest and the dataset's records as raw JSON.
+func readRawRecords(path string) (*PackManifest, []json.RawMessage, error) {
+	manifest, err := LoadPackManifest(manifestPathFor(path))
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := readDataset(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var recs []json.RawMessage
+	if err := json.Unmarshal(data, &recs); err != nil {
+		return nil, nil, fmt.Errorf("decode dataset: %w", err)
+	}
+	return manifest, recs, nil
+}
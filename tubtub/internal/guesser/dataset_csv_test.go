@@ -0,0 +1,102 @@
+package guesser
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	dataset, err := os.ReadFile("../../web/guesser/packs/games/dataset.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := writePack(t, string(dataset), "")
+
+	var out bytes.Buffer
+	if err := ExportCSV(path, &out, DefaultListDelimiter); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := PlanCSVImport(path, bytes.NewReader(out.Bytes()), DefaultListDelimiter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := readDataset(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 0 || len(plan.NotInCSV) != 0 {
+		t.Fatalf("re-importing the export plans %d changes, %d missing ids", len(plan.Changes), len(plan.NotInCSV))
+	}
+	c, err := OpenCatalog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Unchanged != c.Index().Size() {
+		t.Fatalf("unchanged = %d, want %d", plan.Unchanged, c.Index().Size())
+	}
+	if err := plan.Apply("test"); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := readDataset(path); !bytes.Equal(after, before) {
+		t.Fatal("applying an empty import rewrote the dataset")
+	}
+}
+
+func TestCSVImportEdits(t *testing.T) {
+	manifest := `{"fields": {"tags": "list"}}`
+	path := writePack(t, `[
+{"id": 1, "name": "Alpha", "year": 2010, "platforms": ["PC", "Switch"], "tags": ["co-op"]},
+{"id": 2, "name": "Beta", "year": 2012}
+]`, manifest)
+
+	var out bytes.Buffer
+	if err := ExportCSV(path, &out, DefaultListDelimiter); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "tags") {
+		t.Fatalf("export = %q", out.String())
+	}
+
+	// change Beta's year and add a row without an id
+	edited := strings.Join([]string{
+		"id,name,year",
+		"1,Alpha,2010",
+		"2,Beta,2013",
+		",Gamma,2020",
+	}, "\n")
+	plan, err := PlanCSVImport(path, strings.NewReader(edited), DefaultListDelimiter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Unchanged != 1 || len(plan.Changes) != 2 {
+		t.Fatalf("plan = %+v", plan)
+	}
+	if ch := plan.Changes[0]; ch.GameID != 2 || !equalStrings(ch.Fields, []string{"year"}) {
+		t.Fatalf("first change = %+v, want Beta's year", ch)
+	}
+	if ch := plan.Changes[1]; !ch.Added || ch.GameID != 3 || ch.Name != "Gamma" {
+		t.Fatalf("second change = %+v, want Gamma added as 3", ch)
+	}
+	if err := plan.Apply("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := OpenCatalog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx := c.Index()
+	if g := idx.GameByID(2); g == nil || g.Year != 2013 {
+		t.Fatalf("Beta after import = %+v", g)
+	}
+	// columns the CSV left out are kept
+	if got := idx.Registry.Values(idx.GameByID(1), "platforms"); len(got) != 2 {
+		t.Fatalf("Alpha's platforms = %v, want both kept", got)
+	}
+	if g := idx.GameByID(3); g == nil || g.Name != "Gamma" {
+		t.Fatalf("Gamma after import = %+v", g)
+	}
+}
@@ -0,0 +1,273 @@
+package guesser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	lastLoadedFile = "last-loaded.json"
+	changelogFile  = "changelog.json"
+
+	// how long changelog entries are kept, and how many at most
+	changelogKeep       = 180 * 24 * time.Hour
+	maxChangelogEntries = 1000
+	// window /api/dataset/changelog covers unless ?since= says otherwise
+	defaultChangelogWindow = 30 * 24 * time.Hour
+)
+
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type GameChange struct {
+	ID      int           `json:"id"`
+	Name    string        `json:"name"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// DatasetDiff lists what changed between two versions of a dataset, by game ID.
+type DatasetDiff struct {
+	Added   []GameSummary `json:"added"`
+	Removed []GameSummary `json:"removed"`
+	Changed []GameChange  `json:"changed"`
+}
+
+// rawFieldValue reads a field as stored, without placeholder cleaning.
+// Empty lists and nil lists compare equal.
+func rawFieldValue(g *Game, key string) interface{} {
+	var v interface{}
+	if fi, ok := gameJSONFields[key]; ok {
+		v = reflect.ValueOf(g).Elem().Field(fi).Interface()
+	} else {
+		v = g.Extra[key]
+	}
+	if l, ok := v.([]string); ok && len(l) == 0 {
+		return nil
+	}
+	return v
+}
+
+func summarize(g *Game) GameSummary {
+	return GameSummary{ID: g.ID, Name: g.Name, ImageURL: g.ImageURL}
+}
+
+// DiffGames compares two versions of a dataset field by field.
+func DiffGames(manifest *PackManifest, old, new []*Game) *DatasetDiff {
+	diff := &DatasetDiff{Added: []GameSummary{}, Removed: []GameSummary{}, Changed: []GameChange{}}
+	cols := csvColumns(manifest)
+
+	oldByID := map[int]*Game{}
+	for _, g := range old {
+		oldByID[g.ID] = g
+	}
+	newByID := map[int]*Game{}
+	for _, g := range new {
+		newByID[g.ID] = g
+	}
+
+	for _, g := range new {
+		prev, ok := oldByID[g.ID]
+		if !ok {
+			diff.Added = append(diff.Added, summarize(g))
+			continue
+		}
+
+		var changes []FieldChange
+		for _, c := range cols {
+			a, b := rawFieldValue(prev, c.Key), rawFieldValue(g, c.Key)
+			if !reflect.DeepEqual(a, b) {
+				changes = append(changes, FieldChange{Field: c.Key, Old: a, New: b})
+			}
+		}
+		if len(changes) > 0 {
+			diff.Changed = append(diff.Changed, GameChange{ID: g.ID, Name: g.Name, Changes: changes})
+		}
+	}
+
+	for _, g := range old {
+		if _, ok := newByID[g.ID]; !ok {
+			diff.Removed = append(diff.Removed, summarize(g))
+		}
+	}
+
+	sort.Slice(diff.Added, func(a, b int) bool { return diff.Added[a].ID < diff.Added[b].ID })
+	sort.Slice(diff.Removed, func(a, b int) bool { return diff.Removed[a].ID < diff.Removed[b].ID })
+	sort.Slice(diff.Changed, func(a, b int) bool { return diff.Changed[a].ID < diff.Changed[b].ID })
+	return diff
+}
+
+// DiffDatasetFiles compares two dataset files. The manifest next to newPath decides
+// which custom fields are compared; validation problems don't stop the diff.
+func DiffDatasetFiles(oldPath, newPath string) (*DatasetDiff, error) {
+	manifest, err := LoadPackManifest(manifestPathFor(newPath))
+	if err != nil {
+		return nil, err
+	}
+
+	var sides [2][]*Game
+	for i, p := range []string{oldPath, newPath} {
+		data, err := readDataset(p)
+		if err != nil {
+			return nil, err
+		}
+		if sides[i], _, err = parseDataset(data, manifest); err != nil {
+			return nil, err
+		}
+	}
+	return DiffGames(manifest, sides[0], sides[1]), nil
+}
+
+// ----------------------------
+// Changelog
+// ----------------------------
+
+// ChangelogEntry is what one load or admin edit changed.
+type ChangelogEntry struct {
+	At   time.Time    `json:"at"`
+	Diff *DatasetDiff `json:"diff"`
+}
+
+// Changelog is a pack's change history, oldest first. Loads that change nothing
+// add no entry.
+type Changelog struct {
+	Pack    string           `json:"pack"`
+	Entries []ChangelogEntry `json:"entries"`
+}
+
+// Since returns the entries recorded after t.
+func (cl *Changelog) Since(t time.Time) []ChangelogEntry {
+	i := sort.Search(len(cl.Entries), func(i int) bool { return cl.Entries[i].At.After(t) })
+	return append([]ChangelogEntry{}, cl.Entries[i:]...)
+}
+
+func (d *DatasetDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// readChangelog loads the stored history. Files from before the history was kept
+// hold a single diff; that becomes the first entry.
+func readChangelog(path, pack string) *Changelog {
+	cl := &Changelog{Pack: pack, Entries: []ChangelogEntry{}}
+	stored, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("changelog: %v\n", err)
+		}
+		return cl
+	}
+
+	var probe struct {
+		Entries  []ChangelogEntry `json:"entries"`
+		LoadedAt time.Time        `json:"loadedAt"`
+		Diff     *DatasetDiff     `json:"diff"`
+	}
+	if err := json.Unmarshal(stored, &probe); err != nil {
+		log.Printf("changelog: %s: %v\n", path, err)
+		return cl
+	}
+	switch {
+	case probe.Entries != nil:
+		cl.Entries = probe.Entries
+	case probe.Diff != nil && !probe.Diff.empty():
+		cl.Entries = append(cl.Entries, ChangelogEntry{At: probe.LoadedAt, Diff: probe.Diff})
+	}
+	return cl
+}
+
+// updateChangelog compares data with the copy saved when the pack was last loaded
+// and appends what changed to the pack's history. Both live in backups/ so the
+// history survives restarts and deploys.
+func updateChangelog(path string, data []byte, idx *Index) *Changelog {
+	dir := filepath.Join(filepath.Dir(path), backupDir)
+	lastPath := filepath.Join(dir, lastLoadedFile)
+	logPath := filepath.Join(dir, changelogFile)
+
+	cl := readChangelog(logPath, packNameFor(path))
+
+	prev, err := os.ReadFile(lastPath)
+	switch {
+	case err == nil && bytes.Equal(prev, data):
+		return cl
+	case err == nil:
+		old, _, perr := parseDataset(prev, idx.Manifest)
+		if perr != nil {
+			break
+		}
+		// compare against the file as written, not the vocabulary-normalized index
+		cur, _, _ := parseDataset(data, idx.Manifest)
+		if diff := DiffGames(idx.Manifest, old, cur); !diff.empty() {
+			cl.Entries = append(cl.Entries, ChangelogEntry{At: time.Now().UTC(), Diff: diff})
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		log.Printf("changelog: %v\n", err)
+	}
+
+	cl.Entries = cl.Since(time.Now().Add(-changelogKeep))
+	if n := len(cl.Entries) - maxChangelogEntries; n > 0 {
+		cl.Entries = cl.Entries[n:]
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Printf("changelog: %v\n", err)
+		return cl
+	}
+	if err := writeFileAtomic(lastPath, data); err != nil {
+		log.Printf("changelog: %v\n", err)
+	}
+	if enc, err := json.MarshalIndent(cl, "", "  "); err == nil {
+		if err := writeFileAtomic(logPath, enc); err != nil {
+			log.Printf("changelog: %v\n", err)
+		}
+	}
+	return cl
+}
+
+// ----------------------------
+// API: changelog
+// ----------------------------
+
+// DatasetChangelogHandler lists what changed in the pack since ?since= (RFC 3339 or
+// YYYY-MM-DD; default the last 30 days), one entry per load or edit, oldest first.
+func DatasetChangelogHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+
+		since := time.Now().UTC().Add(-defaultChangelogWindow)
+		if s := strings.TrimSpace(r.URL.Query().Get("since")); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				t, err = time.Parse(time.DateOnly, s)
+			}
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid since %q", s), 400)
+				return
+			}
+			since = t.UTC()
+		}
+
+		cl := cat.Changelog()
+		json.NewEncoder(w).Encode(struct {
+			Pack    string           `json:"pack"`
+			Since   time.Time        `json:"since"`
+			Entries []ChangelogEntry `json:"entries"`
+		}{Pack: cl.Pack, Since: since, Entries: cl.Since(since)})
+	})
+}
@@ -0,0 +1,85 @@
+package guesser
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestChangelogAcrossEdits(t *testing.T) {
+	path := writePack(t, editFixture, "")
+	c, err := OpenCatalog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(c.Changelog().Entries); n != 0 {
+		t.Fatalf("first load recorded %d entries, want 0", n)
+	}
+	start := time.Now().UTC()
+
+	if _, err := c.Edit("test", EditPatch, 1, editBody(t, `{"year": 2011}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Edit("test", EditCreate, 0, editBody(t, `{"name": "Gamma", "year": 2020}`)); err != nil {
+		t.Fatal(err)
+	}
+	// reloading unchanged data adds nothing
+	if _, err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(cl *Changelog) {
+		t.Helper()
+		if len(cl.Entries) != 2 {
+			t.Fatalf("got %d entries, want 2", len(cl.Entries))
+		}
+		first, second := cl.Entries[0].Diff, cl.Entries[1].Diff
+		if len(first.Changed) != 1 || first.Changed[0].ID != 1 || first.Changed[0].Changes[0].Field != "year" {
+			t.Fatalf("first entry = %+v, want game 1's year changed", first)
+		}
+		if len(second.Added) != 1 || second.Added[0].Name != "Gamma" || len(second.Changed) != 0 {
+			t.Fatalf("second entry = %+v, want Gamma added", second)
+		}
+		if cl.Entries[0].At.After(cl.Entries[1].At) {
+			t.Fatal("entries out of order")
+		}
+	}
+	check(c.Changelog())
+
+	// the history survives a restart
+	c, err = OpenCatalog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(c.Changelog())
+
+	if n := len(c.Changelog().Since(start.Add(-time.Second))); n != 2 {
+		t.Fatalf("Since(before edits) = %d entries, want 2", n)
+	}
+	if n := len(c.Changelog().Since(time.Now().Add(time.Second))); n != 0 {
+		t.Fatalf("Since(now) = %d entries, want 0", n)
+	}
+}
+
+func TestChangelogReadsLegacyFile(t *testing.T) {
+	path := writePack(t, editFixture, "")
+	dir := filepath.Join(filepath.Dir(path), backupDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	legacy, _ := json.Marshal(map[string]interface{}{
+		"pack":     "games",
+		"loadedAt": "2026-01-02T03:04:05Z",
+		"diff":     DatasetDiff{Added: []GameSummary{{ID: 2, Name: "Beta"}}},
+	})
+	if err := os.WriteFile(filepath.Join(dir, changelogFile), legacy, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cl := readChangelog(filepath.Join(dir, changelogFile), "games")
+	if len(cl.Entries) != 1 || cl.Entries[0].Diff.Added[0].ID != 2 || cl.Entries[0].At.Year() != 2026 {
+		t.Fatalf("legacy changelog read as %+v", cl.Entries)
+	}
+}
@@ -0,0 +1,266 @@
+package guesser
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// earliest release year we accept without flagging it
+const minDatasetYear = 1950
+
+// DatasetIssue is a single problem found while validating a pack's dataset.json.
+type DatasetIssue struct {
+	GameID   int    `json:"gameId"`
+	Field    string `json:"field"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func (i DatasetIssue) String() string {
+	return fmt.Sprintf("%-7s id=%-4d %-22s %s", i.Severity, i.GameID, i.Field, i.Message)
+}
+
+type ValidationReport struct {
+	Issues []DatasetIssue `json:"issues"`
+}
+
+// sortByGame keeps each game's issues together in the lint output.
+func (r *ValidationReport) sortByGame() {
+	sort.SliceStable(r.Issues, func(a, b int) bool {
+		return r.Issues[a].GameID < r.Issues[b].GameID
+	})
+}
+
+func (r *ValidationReport) add(id int, field, severity, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, DatasetIssue{
+		GameID:   id,
+		Field:    field,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *ValidationReport) count(severity string) int {
+	n := 0
+	for _, i := range r.Issues {
+		if i.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *ValidationReport) ErrorCount() int   { return r.count(SeverityError) }
+func (r *ValidationReport) WarningCount() int { return r.count(SeverityWarning) }
+func (r *ValidationReport) HasErrors() bool   { return r.ErrorCount() > 0 }
+
+// gameJSONFields maps every json key of Game to its struct field index.
+var gameJSONFields = func() map[string]int {
+	out := map[string]int{}
+	t := reflect.TypeOf(Game{})
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		out[tag] = i
+	}
+	return out
+}()
+
+// parseDataset decodes dataset.json and validates every record.
+// Decode failures are returned as errors; data problems land in the report.
+func parseDataset(data []byte, manifest *PackManifest) ([]*Game, *ValidationReport, error) {
+	var raw []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("decode dataset: %w", err)
+	}
+
+	var games []*Game
+	if err := json.Unmarshal(data, &games); err != nil {
+		return nil, nil, fmt.Errorf("decode dataset: %w", err)
+	}
+
+	report := &ValidationReport{}
+	for i, g := range games {
+		if g == nil {
+			report.add(0, "", SeverityError, "record %d is null", i)
+			continue
+		}
+		checkUnknownFields(report, g.ID, raw[i], manifest)
+		decodeExtraFields(report, g, raw[i], manifest)
+	}
+	games = compactGames(games)
+	validateGames(report, games)
+
+	report.sortByGame()
+
+	return games, report, nil
+}
+
+func compactGames(in []*Game) []*Game {
+	out := in[:0]
+	for _, g := range in {
+		if g != nil {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+func checkUnknownFields(report *ValidationReport, id int, rec map[string]json.RawMessage, manifest *PackManifest) {
+	var keys []string
+	for k := range rec {
+		if _, ok := gameJSONFields[k]; !ok && manifest.Fields[k] == "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		report.add(id, k, SeverityError, "unknown field")
+	}
+}
+
+// decodeExtraFields copies the pack's declared custom fields into g.Extra.
+func decodeExtraFields(report *ValidationReport, g *Game, rec map[string]json.RawMessage, manifest *PackManifest) {
+	for key, kind := range manifest.Fields {
+		msg, ok := rec[key]
+		if !ok {
+			continue
+		}
+
+		var val interface{}
+		var err error
+		switch kind {
+		case KindString:
+			var s string
+			err = json.Unmarshal(msg, &s)
+			val = s
+		case KindList:
+			var l []string
+			err = json.Unmarshal(msg, &l)
+			val = l
+		case KindInt:
+			var n int
+			err = json.Unmarshal(msg, &n)
+			val = n
+		}
+		if err != nil {
+			report.add(g.ID, key, SeverityError, "expected %s value", kind)
+			continue
+		}
+
+		if g.Extra == nil {
+			g.Extra = map[string]interface{}{}
+		}
+		g.Extra[key] = val
+	}
+}
+
+// validateGames runs the record-level checks shared by load, lint and admin edits.
+func validateGames(report *ValidationReport, games []*Game) {
+	maxYear := time.Now().Year() + 1
+	ids := map[int]*Game{}
+	names := map[string]*Game{}
+
+	for _, g := range games {
+		if g.ID <= 0 {
+			report.add(g.ID, "id", SeverityError, "missing or non-positive id")
+		} else if prev, ok := ids[g.ID]; ok {
+			report.add(g.ID, "id", SeverityError, "duplicate id (also used by %q)", prev.Name)
+		} else {
+			ids[g.ID] = g
+		}
+
+		name := strings.TrimSpace(g.Name)
+		switch {
+		case name == "":
+			report.add(g.ID, "name", SeverityError, "missing name")
+		case isPlaceholder(name):
+			report.add(g.ID, "name", SeverityError, "placeholder name %q", name)
+		default:
+			key := norm(name)
+			if prev, ok := names[key]; ok {
+				report.add(g.ID, "name", SeverityError, "duplicate name %q (also id %d)", name, prev.ID)
+			} else {
+				names[key] = g
+			}
+		}
+
+		switch {
+		case g.Year == 0:
+			report.add(g.ID, "year", SeverityError, "missing year")
+		case g.Year < minDatasetYear || g.Year > maxYear:
+			report.add(g.ID, "year", SeverityWarning, "year %d outside %d-%d", g.Year, minDatasetYear, maxYear)
+		}
+
+		checkPlaceholders(report, g)
+	}
+}
+
+// checkPlaceholders flags string and list values that CleanString/CleanList would drop.
+func checkPlaceholders(report *ValidationReport, g *Game) {
+	v := reflect.ValueOf(g).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if field == "" || field == "name" {
+			continue
+		}
+		fv := v.Field(i)
+
+		switch fv.Kind() {
+		case reflect.String:
+			s := fv.String()
+			if isPlaceholder(s) {
+				report.add(g.ID, field, SeverityWarning, "placeholder value %q", s)
+			}
+		case reflect.Slice:
+			for j := 0; j < fv.Len(); j++ {
+				s := fv.Index(j).String()
+				if strings.TrimSpace(s) == "" {
+					report.add(g.ID, field, SeverityWarning, "empty list entry at %d", j)
+				} else if isPlaceholder(s) {
+					report.add(g.ID, field, SeverityWarning, "placeholder list entry %q", s)
+				}
+			}
+		}
+	}
+}
+
+// LintDataset validates a dataset file without building an Index and warns about
+// likely duplicate entries.
+func LintDataset(path string) (*ValidationReport, error) {
+	data, err := readDataset(path)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := LoadPackManifest(manifestPathFor(path))
+	if err != nil {
+		return nil, err
+	}
+	games, report, err := parseDataset(data, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range FindDuplicates(games, manifest, DuplicateLintThreshold) {
+		if norm(d.A.Name) == norm(d.B.Name) {
+			continue // already a duplicate-name error
+		}
+		report.add(d.B.ID, "name", SeverityWarning, "possible duplicate of %d %q (confidence %.2f: %s)",
+			d.A.ID, d.A.Name, d.Confidence, strings.Join(d.Reasons, ", "))
+	}
+	report.sortByGame()
+	return report, nil
+}
@@ -0,0 +1,136 @@
+package guesser
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// values a list field contributes to a trait
+const dreamTraitValues = 3
+
+// DreamCategory is one slot on the Dream Game Builder board. Fields are the Game
+// fields the slot's trait is read from, first one with a value wins. Subjective
+// slots have no data behind them: the player decides what the game brings.
+type DreamCategory struct {
+	ID         string
+	Label      string
+	Desc       string
+	Group      string
+	Fields     []string
+	Subjective bool
+}
+
+// Board rows, in the order build.js lays them out.
+const (
+	DreamGroupGameplay   = "Core Gameplay"
+	DreamGroupWorld      = "World & Setting"
+	DreamGroupAesthetics = "Aesthetics & Presentation"
+	DreamGroupCreative   = "Creative & Subjective"
+)
+
+var dreamCategories = []DreamCategory{
+	{ID: "core_gameplay_loop", Label: "Core Gameplay Loop", Desc: "Primary interaction pattern", Group: DreamGroupGameplay, Fields: []string{"game_structure", "primary_genre"}},
+	{ID: "combat_style", Label: "Combat Style", Desc: "How conflict is resolved", Group: DreamGroupGameplay, Fields: []string{"combat_style"}},
+	{ID: "movement_system", Label: "Movement System", Desc: "Traversal and locomotion", Group: DreamGroupGameplay, Fields: []string{"movement_type"}},
+	{ID: "enemy_type", Label: "Enemy/Challenge Type", Desc: "What stands in the way", Group: DreamGroupGameplay, Fields: []string{"enemy_types"}},
+	{ID: "progression_system", Label: "Progression & Systems", Desc: "Growth, crafting, rewards", Group: DreamGroupGameplay, Fields: []string{"progression_type"}},
+	{ID: "multiplayer_structure", Label: "Multiplayer Structure", Desc: "How players connect", Group: DreamGroupGameplay, Fields: []string{"multiplayer_type", "multiplayer_presence"}},
+
+	{ID: "world_type", Label: "World Type", Desc: "Open, linear, hub, roguelike", Group: DreamGroupWorld, Fields: []string{"world_type"}},
+	{ID: "setting", Label: "Setting", Desc: "Locale or premise", Group: DreamGroupWorld, Fields: []string{"world_setting"}},
+	{ID: "time_period", Label: "Time Period", Desc: "Era or chronology", Group: DreamGroupWorld, Fields: []string{"time_period"}},
+	{ID: "environment_biomes", Label: "Environment & Biomes", Desc: "Where the game unfolds", Group: DreamGroupWorld, Fields: []string{"environment_type"}},
+	{ID: "narrative_integration", Label: "Narrative Integration", Desc: "Story delivery & presence", Group: DreamGroupWorld, Fields: []string{"story_presence"}},
+
+	{ID: "visual_style", Label: "Visual Style", Desc: "Art direction and palette", Group: DreamGroupAesthetics, Fields: []string{"visual_style"}},
+	{ID: "camera_ui", Label: "Camera & UI Design", Desc: "Viewpoint + HUD feel", Group: DreamGroupAesthetics, Fields: []string{"camera_view"}},
+	// nothing in the dataset describes feel or audio
+	{ID: "animation_feel", Label: "Animation & Game Feel", Desc: "Weight, responsiveness", Group: DreamGroupAesthetics, Subjective: true},
+	{ID: "music_sound", Label: "Music & Sound Direction", Desc: "Sonic landscape", Group: DreamGroupAesthetics, Subjective: true},
+	{ID: "vibe_tone", Label: "Vibe & Tone", Desc: "Atmosphere at a glance", Group: DreamGroupAesthetics, Fields: []string{"overall_tone", "vibe_tags"}},
+
+	{ID: "protagonist_format", Label: "Protagonist Format", Desc: "Who/what you inhabit", Group: DreamGroupCreative, Fields: []string{"protagonist_type"}},
+	{ID: "narrative_themes", Label: "Narrative Themes", Desc: "Big recurring ideas", Group: DreamGroupCreative, Fields: []string{"story_themes"}},
+	{ID: "emotional_intent", Label: "Emotional Intent", Desc: "Player feeling target", Group: DreamGroupCreative, Fields: []string{"player_emotion"}},
+	{ID: "wildcard_twist", Label: "Wildcard Creative Twist", Desc: "Anything chaotic", Group: DreamGroupCreative, Fields: []string{"special_mechanics", "iconic_features"}},
+}
+
+func dreamCategory(id string) (DreamCategory, bool) {
+	for _, c := range dreamCategories {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return DreamCategory{}, false
+}
+
+// dreamTrait is what g contributes to category c, or "" if the category isn't backed
+// by a field or g has no value for it.
+func dreamTrait(reg *CategoryRegistry, c DreamCategory, g *Game) string {
+	for _, key := range c.Fields {
+		vals := reg.Values(g, key)
+		if len(vals) > dreamTraitValues {
+			vals = vals[:dreamTraitValues]
+		}
+		if len(vals) > 0 {
+			return strings.Join(vals, ", ")
+		}
+	}
+	return ""
+}
+
+// ----------------------------
+// API: builder categories
+// ----------------------------
+
+type DreamCategoryField struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+}
+
+type DreamCategoryInfo struct {
+	ID         string               `json:"id"`
+	Label      string               `json:"label"`
+	Desc       string               `json:"desc"`
+	Group      string               `json:"group"`
+	Subjective bool                 `json:"subjective"`
+	Fields     []DreamCategoryField `json:"fields"`
+}
+
+type DreamCategoriesResponse struct {
+	Pack       string              `json:"pack"`
+	Groups     []string            `json:"groups"`
+	Categories []DreamCategoryInfo `json:"categories"`
+}
+
+// DreamCategoriesHandler serves /api/dream/categories: the board's slots in order and
+// the ?pack= fields each one reads its trait from. A slot whose fields the pack
+// doesn't have is reported as subjective.
+func DreamCategoriesHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+		reg := cat.Index().Registry
+
+		out := DreamCategoriesResponse{Pack: cat.Name(), Groups: []string{}, Categories: []DreamCategoryInfo{}}
+		for _, c := range dreamCategories {
+			if len(out.Groups) == 0 || out.Groups[len(out.Groups)-1] != c.Group {
+				out.Groups = append(out.Groups, c.Group)
+			}
+			info := DreamCategoryInfo{ID: c.ID, Label: c.Label, Desc: c.Desc, Group: c.Group, Fields: []DreamCategoryField{}}
+			for _, key := range c.Fields {
+				if d, ok := reg.Def(key); ok {
+					info.Fields = append(info.Fields, DreamCategoryField{Key: d.Key, Label: d.Label})
+				}
+			}
+			info.Subjective = c.Subjective || len(info.Fields) == 0
+			out.Categories = append(out.Categories, info)
+		}
+
+		json.NewEncoder(w).Encode(out)
+	})
+}
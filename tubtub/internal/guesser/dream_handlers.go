@@ -2,19 +2,295 @@ package guesser
 
 import (
 	"encoding/json"
-	"math/rand"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tubtub/internal/webutil"
 )
 
-func DreamRollHandler(idx *Index) http.Handler {
+type DreamRollResponse struct {
+	Game *Game  `json:"game"`
+	Seed string `json:"seed,omitempty"`
+	// games that could have been drawn, this one included
+	Pool int `json:"pool"`
+}
+
+// DreamRollHandler draws one game from the ?pack= dataset.
+//
+//	?genre=, ?platform=  match primary/sub genres and platforms by substring (repeat or comma-separate)
+//	?era=                1990s, 2010..2015 or 2020
+//	?exclude=            game IDs not to draw, e.g. the ones already rolled
+//	?seed=               draw in a fixed order for this seed; ?n= skips ahead in it
+//
+// Passing the same seed and filters with the growing exclude list gives everyone
+// the same sequence.
+func DreamRollHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+		idx := cat.Index()
+		q := r.URL.Query()
+
+		exclude, err := excludeFromQuery(q)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		skip := 0
+		if s := q.Get("n"); s != "" {
+			skip, err = strconv.Atoi(s)
+			if err != nil || skip < 0 {
+				http.Error(w, "invalid n", 400)
+				return
+			}
+		}
+
+		spec := rollSpecFromQuery(q)
+		g, pool, err := spec.Draw(idx.Games, exclude, skip)
+		switch {
+		case errors.Is(err, ErrDreamNoMatch):
+			http.Error(w, err.Error(), 404)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), 400)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(w).Encode(DreamRollResponse{Game: g, Seed: spec.Seed, Pool: pool})
+	})
+}
+
+// DreamCreateRequest picks the board's categories and, optionally, a seed and the
+// filters /api/dream/roll takes.
+type DreamCreateRequest struct {
+	Categories []string `json:"categories"`
+	DreamRollSpec
+}
+
+type DreamAssignRequest struct {
+	Category string `json:"category"`
+}
+
+// DreamShareRequest sets how long a share link lives; 0 or missing never expires.
+type DreamShareRequest struct {
+	ExpiresInDays int `json:"expiresInDays"`
+}
+
+// DreamSessionsHandler runs server-side builder boards:
+//
+//	POST /api/dream/sessions?pack=        start a board ({"categories": [...]}, empty for all,
+//	                                      plus optional "seed", "genre", "platform", "era")
+//	GET  /api/dream/sessions/{id}         board state, or the finished dream game
+//	POST /api/dream/sessions/{id}/roll    draw a game not rolled on this board yet
+//	POST /api/dream/sessions/{id}/assign  lock the roll into {"category": "..."}
+//	POST /api/dream/sessions/{id}/share   publish the finished board ({"expiresInDays": N});
+//	                                      sharing again keeps the link and resets its expiry
+//	GET  /api/dream/sessions/{id}/pitch   the finished board's pitch and nearest real games
+func DreamSessionsHandler(lib *Library, store *DreamStore, pitches *PitchBook) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/dream/sessions"), "/")
+		id, action, _ := strings.Cut(rest, "/")
+
+		var view *DreamSessionView
+		var err error
+		status := 200
+		switch {
+		case id == "" && r.Method == http.MethodPost:
+			cat, rerr := lib.Resolve(r)
+			if rerr != nil {
+				http.Error(w, rerr.Error(), 404)
+				return
+			}
+			var req DreamCreateRequest
+			if r.ContentLength != 0 {
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					http.Error(w, "invalid json", 400)
+					return
+				}
+			}
+			view, err = store.Create(cat, req.Categories, req.DreamRollSpec, webutil.ClientIP(r))
+			if err != nil {
+				writeDreamError(w, err)
+				return
+			}
+			status = 201
+		case id == "":
+			http.Error(w, "method not allowed", 405)
+			return
+		case action == "" && r.Method == http.MethodGet:
+			view, err = store.Get(id)
+		case action == "roll" && r.Method == http.MethodPost:
+			view, err = store.Roll(lib, id)
+		case action == "assign" && r.Method == http.MethodPost:
+			var req DreamAssignRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid json", 400)
+				return
+			}
+			view, err = store.Assign(lib, id, strings.TrimSpace(req.Category))
+		case action == "share" && r.Method == http.MethodPost:
+			var req DreamShareRequest
+			if r.ContentLength != 0 {
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					http.Error(w, "invalid json", 400)
+					return
+				}
+			}
+			sh, created, err := store.Share(id, time.Duration(req.ExpiresInDays)*24*time.Hour)
+			if err != nil {
+				writeDreamError(w, err)
+				return
+			}
+			if created {
+				status = 201
+			}
+			writeJSONStatus(w, status, sh)
+			return
+		case action == "pitch" && r.Method == http.MethodGet:
+			view, err := store.Get(id)
+			if err == nil && view.Dream == nil {
+				err = ErrDreamUnfinished
+			}
+			if err != nil {
+				writeDreamError(w, err)
+				return
+			}
+			writeDreamPitch(w, lib, pitches, view.Dream)
+			return
+		case action == "" || action == "roll" || action == "assign" || action == "share" || action == "pitch":
+			http.Error(w, "method not allowed", 405)
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		if err != nil {
+			writeDreamError(w, err)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		writeJSONStatus(w, status, view)
+	})
+}
+
+// writeDreamError maps builder errors onto status codes: broken rules are conflicts,
+// anything unrecognised is a bad request.
+func writeDreamError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrDreamNotFound), errors.Is(err, ErrDreamNoShare):
+		http.Error(w, err.Error(), 404)
+	case errors.Is(err, ErrDreamPending), errors.Is(err, ErrDreamNoRoll), errors.Is(err, ErrDreamFilled),
+		errors.Is(err, ErrDreamFinished), errors.Is(err, ErrDreamExhausted), errors.Is(err, ErrDreamUnfinished),
+		errors.Is(err, ErrVoteAddrUsed):
+		http.Error(w, err.Error(), 409)
+	case errors.Is(err, ErrDreamPackMissing), errors.Is(err, ErrDreamExpired):
+		http.Error(w, err.Error(), 410)
+	case errors.Is(err, ErrVoteRateLimited), errors.Is(err, ErrDreamRateLimited):
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, err.Error(), 429)
+	case errors.Is(err, ErrDreamFull):
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, err.Error(), 503)
+	default:
+		http.Error(w, err.Error(), 400)
+	}
+}
+
+// writeDreamPitch composes the pitch for a finished board against its pack.
+func writeDreamPitch(w http.ResponseWriter, lib *Library, pitches *PitchBook, d *DreamGame) {
+	cat := lib.Pack(d.Pack)
+	if cat == nil {
+		writeDreamError(w, ErrDreamPackMissing)
+		return
+	}
+	t, err := pitches.Templates()
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	json.NewEncoder(w).Encode(ComposePitch(cat.Index(), t, d))
+}
+
+// DreamVoteRequest names the anonymous player voting; the X-Player-ID header works too.
+type DreamVoteRequest struct {
+	PlayerID string `json:"playerId"`
+}
+
+// DreamShareHandler serves shared dream games:
+//
+//	GET    /api/dream/{slug}        the share
+//	GET    /api/dream/{slug}/pitch  its pitch
+//	POST   /api/dream/{slug}/vote   upvote once per player ({"playerId": "..."})
+//	DELETE /api/dream/{slug}/vote   take the vote back
+func DreamShareHandler(lib *Library, store *DreamStore, pitches *PitchBook) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slug, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/dream/"), "/"), "/")
+		if slug == "" || (action != "" && action != "pitch" && action != "vote") {
+			http.NotFound(w, r)
+			return
+		}
+
+		if action == "vote" {
+			if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+				http.Error(w, "method not allowed", 405)
+				return
+			}
+			var req DreamVoteRequest
+			if r.ContentLength != 0 {
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					http.Error(w, "invalid json", 400)
+					return
+				}
+			}
+			if req.PlayerID == "" {
+				req.PlayerID = r.Header.Get("X-Player-ID")
+			}
+			res, err := store.Vote(slug, strings.TrimSpace(req.PlayerID), webutil.ClientIP(r), r.Method == http.MethodPost)
+			if err != nil {
+				writeDreamError(w, err)
+				return
+			}
+			json.NewEncoder(w).Encode(res)
+			return
+		}
 
-		g := idx.Games[rand.Intn(idx.Size())]
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", 405)
+			return
+		}
 
+		sh, err := store.Shared(slug)
+		if err != nil {
+			writeDreamError(w, err)
+			return
+		}
+		if action == "pitch" {
+			writeDreamPitch(w, lib, pitches, sh.Dream)
+			return
+		}
+		json.NewEncoder(w).Encode(store.FeedItem(sh))
+	})
+}
+
+// DreamFeedHandler serves /api/dream/top (most votes first) or /api/dream/recent
+// (newest first) over live shares. ?limit= defaults to 20.
+func DreamFeedHandler(store *DreamStore, top bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := defaultDreamFeedLimit
+		if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 {
+			limit = min(n, maxDreamFeedLimit)
+		}
 		json.NewEncoder(w).Encode(struct {
-			Game *Game `json:"game"`
-		}{
-			Game: g,
-		})
+			Items []DreamFeedItem `json:"items"`
+		}{store.Feed(top, limit)})
 	})
 }
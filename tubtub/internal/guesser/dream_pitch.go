@@ -0,0 +1,235 @@
+package guesser
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const (
+	// real games listed under a pitch
+	dreamNearestGames = 5
+	// values a list field contributes to one placeholder
+	pitchListValues    = 2
+	pitchFallbackTitle = "Untitled Dream Game"
+)
+
+// {field} or {field.from}
+var pitchPlaceholder = regexp.MustCompile(`\{([a-z0-9_]+)(\.from)?\}`)
+
+// PitchTemplates is the editable template file. Each group adds at most one
+// sentence to the pitch.
+type PitchTemplates struct {
+	Titles []string   `json:"titles"`
+	Groups [][]string `json:"groups"`
+}
+
+// PitchBook loads the templates from path and reloads them when the file changes,
+// so they can be edited without a restart.
+type PitchBook struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	tmpl    *PitchTemplates
+}
+
+func NewPitchBook(path string) *PitchBook {
+	return &PitchBook{path: path}
+}
+
+func (b *PitchBook) Templates() (*PitchTemplates, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	fi, err := os.Stat(b.path)
+	if err != nil {
+		if b.tmpl != nil {
+			return b.tmpl, nil
+		}
+		return nil, fmt.Errorf("pitch templates: %w", err)
+	}
+	if b.tmpl != nil && fi.ModTime().Equal(b.modTime) {
+		return b.tmpl, nil
+	}
+
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		return nil, fmt.Errorf("pitch templates: %w", err)
+	}
+	var t PitchTemplates
+	if err := json.Unmarshal(data, &t); err != nil {
+		// keep serving the last good copy while the file is mid-edit
+		if b.tmpl != nil {
+			return b.tmpl, nil
+		}
+		return nil, fmt.Errorf("pitch templates: %w", err)
+	}
+	b.tmpl, b.modTime = &t, fi.ModTime()
+	return b.tmpl, nil
+}
+
+// DreamPitch is the composed text for a finished board plus the real games it's
+// closest to. The board's own source games are left out of Nearest.
+type DreamPitch struct {
+	Title     string        `json:"title"`
+	Pitch     string        `json:"pitch"`
+	Sentences []string      `json:"sentences"`
+	Nearest   []SimilarGame `json:"nearest"`
+}
+
+// dreamSource is a field value on the board and the game it came from.
+type dreamSource struct {
+	values []string
+	from   string
+}
+
+// dreamFeatures collects, per Game field, the values the board's slots draw from
+// their source games. Games a reload removed are skipped.
+func dreamFeatures(idx *Index, d *DreamGame) (map[string]dreamSource, map[int]bool) {
+	feats := map[string]dreamSource{}
+	sources := map[int]bool{}
+	for _, slot := range d.Slots {
+		if slot.Game == nil {
+			continue
+		}
+		g := idx.GameByID(slot.Game.ID)
+		if g == nil || norm(g.Name) != norm(slot.Game.Name) {
+			continue
+		}
+		sources[g.ID] = true
+
+		c, _ := dreamCategory(slot.Category)
+		for _, key := range c.Fields {
+			if _, taken := feats[key]; taken {
+				continue
+			}
+			if vals := idx.Registry.Values(g, key); len(vals) > 0 && !isAbsent(vals) {
+				feats[key] = dreamSource{values: vals, from: g.Name}
+			}
+		}
+	}
+	return feats, sources
+}
+
+// lowerFirst lowercases a value's first letter so it reads mid-sentence, unless
+// its first word looks like an acronym or name ("PvP", "FPS", "GTA-like").
+func lowerFirst(s string) string {
+	word, _, _ := strings.Cut(s, " ")
+	r := []rune(word)
+	if len(r) < 2 || !unicode.IsUpper(r[0]) || !unicode.IsLower(r[1]) || strings.ContainsFunc(string(r[1:]), unicode.IsUpper) {
+		return s
+	}
+	return string(unicode.ToLower(r[0])) + s[len(string(r[0])):]
+}
+
+func upperFirst(s string) string {
+	r := []rune(s)
+	if len(r) == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(r[0])) + string(r[1:])
+}
+
+// fillPitch substitutes every placeholder in line, or reports false if one has no value.
+func fillPitch(line string, feats map[string]dreamSource) (string, bool) {
+	ok := true
+	out := pitchPlaceholder.ReplaceAllStringFunc(line, func(m string) string {
+		sub := pitchPlaceholder.FindStringSubmatch(m)
+		src, has := feats[sub[1]]
+		if !has {
+			ok = false
+			return m
+		}
+		if sub[2] != "" {
+			return src.from
+		}
+		vals := src.values
+		if len(vals) > pitchListValues {
+			vals = vals[:pitchListValues]
+		}
+		lowered := make([]string, len(vals))
+		for i, v := range vals {
+			lowered[i] = lowerFirst(v)
+		}
+		return strings.Join(lowered, " and ")
+	})
+	return upperFirst(out), ok
+}
+
+// pickPitch returns the first usable line, starting from a spot fixed by key so the
+// same board always reads the same.
+func pickPitch(lines []string, key string, feats map[string]dreamSource) (string, bool) {
+	if len(lines) == 0 {
+		return "", false
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	start := int(h.Sum32() % uint32(len(lines)))
+	for i := range lines {
+		if s, ok := fillPitch(lines[(start+i)%len(lines)], feats); ok {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// ComposePitch writes the pitch for a finished board from the pack's live data.
+func ComposePitch(idx *Index, t *PitchTemplates, d *DreamGame) *DreamPitch {
+	feats, sources := dreamFeatures(idx, d)
+
+	p := &DreamPitch{Title: pitchFallbackTitle, Sentences: []string{}, Nearest: []SimilarGame{}}
+	if s, ok := pickPitch(t.Titles, d.ID+"/title", feats); ok {
+		p.Title = s
+	}
+	for i, group := range t.Groups {
+		if s, ok := pickPitch(group, fmt.Sprintf("%s/%d", d.ID, i), feats); ok {
+			p.Sentences = append(p.Sentences, s)
+		}
+	}
+	p.Pitch = strings.Join(p.Sentences, " ")
+
+	// nearest: the board's traits compared like any two games are
+	dream := gameFeatures{}
+	for key, src := range feats {
+		dream[key] = src.values
+	}
+	type scored struct {
+		g     *Game
+		f     gameFeatures
+		score float64
+	}
+	var ranked []scored
+	for _, g := range idx.Games {
+		if sources[g.ID] {
+			continue
+		}
+		f := featuresOf(idx.Registry, g)
+		if s := similarity(idx.Registry, dream, f); s > 0 {
+			ranked = append(ranked, scored{g, f, s})
+		}
+	}
+	sort.Slice(ranked, func(a, b int) bool {
+		if ranked[a].score != ranked[b].score {
+			return ranked[a].score > ranked[b].score
+		}
+		return ranked[a].g.ID < ranked[b].g.ID
+	})
+	for _, r := range ranked[:min(len(ranked), dreamNearestGames)] {
+		shared := sharedFields(idx.Registry, dream, r.f)
+		p.Nearest = append(p.Nearest, SimilarGame{
+			GameSummary: summarize(r.g),
+			Score:       float64(int(r.score*1000+0.5)) / 1000,
+			Summary:     sharedSummary(shared),
+			Shared:      shared,
+		})
+	}
+	return p
+}
@@ -0,0 +1,159 @@
+package guesser
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrDreamNoMatch = errors.New("no games match the roll filters")
+
+// DreamRollSpec narrows and orders the games a roll draws from. With a seed the
+// draw order is a fixed shuffle of the matching games, so the same seed and
+// filters give the same sequence for everyone.
+type DreamRollSpec struct {
+	Seed      string   `json:"seed,omitempty"`
+	Genres    []string `json:"genre,omitempty"`
+	Platforms []string `json:"platform,omitempty"`
+	Era       string   `json:"era,omitempty"`
+}
+
+// parseEra turns "1990s", "2010..2015" or "2020" into an intMatches range.
+func parseEra(era string) (string, error) {
+	era = strings.TrimSpace(era)
+	if era == "" {
+		return "", nil
+	}
+	if d, ok := strings.CutSuffix(era, "s"); ok {
+		n, err := strconv.Atoi(d)
+		if err != nil || n%10 != 0 {
+			return "", fmt.Errorf("invalid era %q (use 1990s, 2010..2015 or 2020)", era)
+		}
+		return fmt.Sprintf("%d..%d", n, n+9), nil
+	}
+	bounds := []string{era}
+	if lo, hi, ok := strings.Cut(era, ".."); ok {
+		bounds = []string{lo, hi} // either end may be open
+	}
+	for _, s := range bounds {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(s); err != nil {
+			return "", fmt.Errorf("invalid era %q (use 1990s, 2010..2015 or 2020)", era)
+		}
+	}
+	return era, nil
+}
+
+// containsFold reports whether any of have contains any of want, ignoring case.
+func containsFold(have []string, want []string) bool {
+	for _, w := range want {
+		w = strings.ToLower(strings.TrimSpace(w))
+		for _, h := range have {
+			if w != "" && strings.Contains(strings.ToLower(h), w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pool is the games matching the filters, in draw order.
+func (s DreamRollSpec) pool(games []*Game) ([]*Game, error) {
+	era, err := parseEra(s.Era)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*Game
+	for _, g := range games {
+		if len(s.Genres) > 0 && !containsFold(append([]string{g.PrimaryGenre}, g.SubGenres...), s.Genres) {
+			continue
+		}
+		if len(s.Platforms) > 0 && !containsFold(g.Platforms, s.Platforms) {
+			continue
+		}
+		if era != "" && (g.Year == 0 || !intMatches(g.Year, era)) {
+			continue
+		}
+		out = append(out, g)
+	}
+
+	if s.Seed != "" {
+		// shuffle from ID order so the sequence doesn't depend on the file's ordering
+		sort.Slice(out, func(a, b int) bool { return out[a].ID < out[b].ID })
+		h := fnv.New64a()
+		h.Write([]byte(s.Seed))
+		rng := rand.New(rand.NewSource(int64(h.Sum64())))
+		rng.Shuffle(len(out), func(a, b int) { out[a], out[b] = out[b], out[a] })
+	}
+	return out, nil
+}
+
+// Draw picks the skip'th game of the pool that isn't excluded: in seed order when
+// there is a seed, otherwise at random (skip is ignored). It also returns how many
+// games were left to draw from.
+func (s DreamRollSpec) Draw(games []*Game, exclude map[int]bool, skip int) (*Game, int, error) {
+	pool, err := s.pool(games)
+	if err != nil {
+		return nil, 0, err
+	}
+	var fresh []*Game
+	for _, g := range pool {
+		if !exclude[g.ID] {
+			fresh = append(fresh, g)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil, 0, ErrDreamNoMatch
+	}
+	if s.Seed == "" {
+		return fresh[rand.Intn(len(fresh))], len(fresh), nil
+	}
+	if skip >= len(fresh) {
+		return nil, len(fresh), ErrDreamNoMatch
+	}
+	return fresh[skip], len(fresh), nil
+}
+
+// rollSpecFromQuery reads ?seed=, ?genre=, ?platform= (both repeatable) and ?era=.
+func rollSpecFromQuery(q url.Values) DreamRollSpec {
+	return DreamRollSpec{
+		Seed:      strings.TrimSpace(q.Get("seed")),
+		Genres:    splitParam(q["genre"]),
+		Platforms: splitParam(q["platform"]),
+		Era:       q.Get("era"),
+	}
+}
+
+// splitParam flattens repeated and comma-separated query values.
+func splitParam(vals []string) []string {
+	var out []string
+	for _, v := range vals {
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
+
+// excludeFromQuery reads ?exclude=12,40&exclude=7.
+func excludeFromQuery(q url.Values) (map[int]bool, error) {
+	out := map[int]bool{}
+	for _, p := range splitParam(q["exclude"]) {
+		id, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude id %q", p)
+		}
+		out[id] = true
+	}
+	return out, nil
+}
@@ -0,0 +1,390 @@
+package guesser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"tubtub/internal/webutil"
+)
+
+const (
+	// unfinished builder sessions idle for longer than this are dropped
+	dreamSessionTTL = 6 * time.Hour
+	// sessions kept in memory at once, and how many one address may start
+	maxDreamSessions      = 5000
+	dreamCreatesPerWindow = 20
+	dreamCreateWindow     = 10 * time.Minute
+)
+
+var (
+	ErrDreamNotFound    = errors.New("dream session not found")
+	ErrDreamPending     = errors.New("assign the current roll before rolling again")
+	ErrDreamNoRoll      = errors.New("roll a game first")
+	ErrDreamFilled      = errors.New("category already filled")
+	ErrDreamFinished    = errors.New("dream game already finished")
+	ErrDreamExhausted   = errors.New("no games left to roll")
+	ErrDreamPackMissing = errors.New("the session's pack is no longer loaded")
+	ErrDreamRateLimited = errors.New("too many new boards, slow down")
+	ErrDreamFull        = errors.New("too many boards in progress, try again later")
+)
+
+// DreamPick is the game locked into a category and the trait it lends it.
+type DreamPick struct {
+	GameSummary
+	Year         int    `json:"year"`
+	PrimaryGenre string `json:"primary_genre"`
+	Trait        string `json:"trait,omitempty"`
+}
+
+// DreamSlot is one category on a board. Subjective slots have no trait to show.
+type DreamSlot struct {
+	Category   string     `json:"category"`
+	Label      string     `json:"label"`
+	Group      string     `json:"group"`
+	Subjective bool       `json:"subjective,omitempty"`
+	Game       *DreamPick `json:"game"`
+}
+
+// DreamGame is a finished board. It is written to the store's directory and its
+// owner can fetch it by session ID after the session itself is gone. ID is a
+// separate public ID, safe to put in shares: the session ID is what lets someone
+// read or share the board.
+type DreamGame struct {
+	ID         string      `json:"id"`
+	Pack       string      `json:"pack"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	FinishedAt time.Time   `json:"finishedAt"`
+	Slots      []DreamSlot `json:"slots"`
+}
+
+// DreamSessionView is what the builder endpoints return.
+type DreamSessionView struct {
+	SessionID string      `json:"sessionId"`
+	Pack      string      `json:"pack"`
+	Seed      string      `json:"seed,omitempty"`
+	Slots     []DreamSlot `json:"slots"`
+	Pending   *Game       `json:"pending,omitempty"`
+	Remaining int         `json:"remaining"`
+	Finished  bool        `json:"finished"`
+	Dream     *DreamGame  `json:"dream,omitempty"`
+}
+
+// DreamSession is one board in progress: the chosen categories, what has been
+// rolled so far and the roll waiting to be assigned.
+type DreamSession struct {
+	ID        string
+	Pack      string
+	CreatedAt time.Time
+
+	spec       DreamRollSpec
+	categories []DreamCategory
+	picks      map[string]*DreamPick
+	rolled     map[int]bool
+	pending    *Game
+	dream      *DreamGame
+	touched    time.Time
+}
+
+func (s *DreamSession) slots() []DreamSlot {
+	out := make([]DreamSlot, 0, len(s.categories))
+	for _, c := range s.categories {
+		out = append(out, DreamSlot{Category: c.ID, Label: c.Label, Group: c.Group, Subjective: c.Subjective, Game: s.picks[c.ID]})
+	}
+	return out
+}
+
+func (s *DreamSession) view() *DreamSessionView {
+	return &DreamSessionView{
+		SessionID: s.ID,
+		Pack:      s.Pack,
+		Seed:      s.spec.Seed,
+		Slots:     s.slots(),
+		Pending:   s.pending,
+		Remaining: len(s.categories) - len(s.picks),
+		Finished:  s.dream != nil,
+		Dream:     s.dream,
+	}
+}
+
+// DreamStore keeps builder sessions in memory and finished dream games, shares and
+// votes in dir. An empty dir keeps everything in memory only.
+type DreamStore struct {
+	mu       sync.Mutex
+	dir      string
+	sessions map[string]*DreamSession
+	shares   map[string]*DreamShare
+	votes    map[string]*dreamVotes
+	limits   dreamVoteLimits
+	creates  *webutil.RateLimiter
+}
+
+func NewDreamStore(dir string) *DreamStore {
+	if dir != "" {
+		if err := os.MkdirAll(filepath.Join(dir, dreamSharesDir), 0755); err != nil {
+			log.Printf("dream store: %v (finished games won't persist)\n", err)
+			dir = ""
+		}
+	}
+	s := &DreamStore{
+		dir:      dir,
+		sessions: make(map[string]*DreamSession),
+		shares:   make(map[string]*DreamShare),
+		votes:    make(map[string]*dreamVotes),
+		limits:   newDreamVoteLimits(),
+		creates:  webutil.NewRateLimiter(dreamCreatesPerWindow, dreamCreateWindow),
+	}
+	if dir != "" {
+		s.loadShares()
+		s.loadVotes()
+	}
+	return s
+}
+
+// Create starts a board with the given category IDs, kept in board order. No IDs
+// means every category. Rolls come from the games matching spec; boards with the
+// same seed and filters roll the same games in the same order. ip is the caller,
+// for the per-address limit on new boards.
+func (s *DreamStore) Create(cat *Catalog, ids []string, spec DreamRollSpec, ip string) (*DreamSessionView, error) {
+	if !s.creates.Allow(ip) {
+		return nil, ErrDreamRateLimited
+	}
+	if cat.Index().Size() == 0 {
+		return nil, errors.New("dataset empty")
+	}
+
+	want := map[string]bool{}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if _, ok := dreamCategory(id); !ok {
+			return nil, fmt.Errorf("unknown category %q", id)
+		}
+		want[id] = true
+	}
+	var chosen []DreamCategory
+	for _, c := range dreamCategories {
+		if len(want) == 0 || want[c.ID] {
+			chosen = append(chosen, c)
+		}
+	}
+
+	pool, err := spec.pool(cat.Index().Games)
+	if err != nil {
+		return nil, err
+	}
+	if len(pool) < len(chosen) {
+		return nil, fmt.Errorf("only %d games match the roll filters; the board needs %d", len(pool), len(chosen))
+	}
+
+	now := time.Now()
+	sess := &DreamSession{
+		ID:         newSessionID(),
+		Pack:       cat.Name(),
+		CreatedAt:  now,
+		spec:       spec,
+		categories: chosen,
+		picks:      map[string]*DreamPick{},
+		rolled:     map[int]bool{},
+		touched:    now,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(now)
+	if len(s.sessions) >= maxDreamSessions {
+		return nil, ErrDreamFull
+	}
+	s.sessions[sess.ID] = sess
+	return sess.view(), nil
+}
+
+// prune drops sessions nobody has touched in a while. Callers hold s.mu.
+func (s *DreamStore) prune(now time.Time) {
+	for id, sess := range s.sessions {
+		if now.Sub(sess.touched) > dreamSessionTTL {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+func (s *DreamStore) withSession(id string, fn func(*DreamSession) error) error {
+	id = strings.TrimSpace(id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		return ErrDreamNotFound
+	}
+	sess.touched = time.Now()
+	return fn(sess)
+}
+
+// Get returns a session in progress, or the finished dream game saved under id.
+func (s *DreamStore) Get(id string) (*DreamSessionView, error) {
+	var out *DreamSessionView
+	err := s.withSession(id, func(sess *DreamSession) error {
+		out = sess.view()
+		return nil
+	})
+	if !errors.Is(err, ErrDreamNotFound) {
+		return out, err
+	}
+
+	dream, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	return &DreamSessionView{
+		SessionID: strings.TrimSpace(id),
+		Pack:      dream.Pack,
+		Slots:     dream.Slots,
+		Finished:  true,
+		Dream:     dream,
+	}, nil
+}
+
+// Roll draws a game the session hasn't seen yet from its pack, in seed order if the
+// board has a seed. The previous roll has to be assigned first.
+func (s *DreamStore) Roll(lib *Library, id string) (*DreamSessionView, error) {
+	var out *DreamSessionView
+	err := s.withSession(id, func(sess *DreamSession) error {
+		switch {
+		case sess.dream != nil:
+			return ErrDreamFinished
+		case sess.pending != nil:
+			return ErrDreamPending
+		}
+		cat := lib.Pack(sess.Pack)
+		if cat == nil {
+			return ErrDreamPackMissing
+		}
+
+		g, _, err := sess.spec.Draw(cat.Index().Games, sess.rolled, 0)
+		if errors.Is(err, ErrDreamNoMatch) {
+			return ErrDreamExhausted
+		}
+		if err != nil {
+			return err
+		}
+		sess.rolled[g.ID] = true
+		sess.pending = g
+		out = sess.view()
+		return nil
+	})
+	return out, err
+}
+
+// Assign locks the pending roll into one of the session's empty categories. Filling
+// the last one finishes the board and saves it.
+func (s *DreamStore) Assign(lib *Library, id, category string) (*DreamSessionView, error) {
+	var out *DreamSessionView
+	err := s.withSession(id, func(sess *DreamSession) error {
+		if sess.dream != nil {
+			return ErrDreamFinished
+		}
+		var slot *DreamCategory
+		for i, c := range sess.categories {
+			if c.ID == category {
+				slot = &sess.categories[i]
+			}
+		}
+		switch {
+		case slot == nil:
+			return fmt.Errorf("category %q is not on this board", category)
+		case sess.picks[category] != nil:
+			return ErrDreamFilled
+		case sess.pending == nil:
+			return ErrDreamNoRoll
+		}
+
+		g := sess.pending
+		pick := &DreamPick{
+			GameSummary:  GameSummary{ID: g.ID, Name: g.Name, ImageURL: g.ImageURL},
+			Year:         g.Year,
+			PrimaryGenre: CleanString(g.PrimaryGenre),
+		}
+		if cat := lib.Pack(sess.Pack); cat != nil {
+			pick.Trait = dreamTrait(cat.Index().Registry, *slot, g)
+		}
+		sess.picks[category] = pick
+		sess.pending = nil
+
+		if len(sess.picks) == len(sess.categories) {
+			sess.dream = &DreamGame{
+				ID:         newDreamSlug(),
+				Pack:       sess.Pack,
+				CreatedAt:  sess.CreatedAt.UTC(),
+				FinishedAt: time.Now().UTC(),
+				Slots:      sess.slots(),
+			}
+			if err := s.save(sess.ID, sess.dream); err != nil {
+				// the board is still finished; it just can't be fetched after a restart
+				log.Printf("dream store: %v\n", err)
+			}
+		}
+		out = sess.view()
+		return nil
+	})
+	return out, err
+}
+
+// pathFor is where a saved document lives under the store's dir ("" for finished
+// dream games, dreamSharesDir for shares).
+func (s *DreamStore) pathFor(sub, id string) (string, bool) {
+	// IDs and slugs are lowercase hex/base32; anything else can't name a file of ours
+	if s.dir == "" || id == "" || strings.Trim(id, "0123456789abcdefghijklmnopqrstuvwxyz") != "" {
+		return "", false
+	}
+	return filepath.Join(s.dir, sub, id+".json"), true
+}
+
+// save writes a finished board under its session's ID.
+func (s *DreamStore) save(sessionID string, d *DreamGame) error {
+	path, ok := s.pathFor("", sessionID)
+	if !ok {
+		return nil
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("save dream %s: %w", d.ID, err)
+	}
+	return nil
+}
+
+// load reads the board saved for a session. Boards saved before they had a public
+// ID of their own carried the session ID; they get one here.
+func (s *DreamStore) load(id string) (*DreamGame, error) {
+	path, ok := s.pathFor("", strings.TrimSpace(id))
+	if !ok {
+		return nil, ErrDreamNotFound
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrDreamNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var d DreamGame
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("decode dream %s: %w", id, err)
+	}
+	if d.ID == strings.TrimSpace(id) {
+		d.ID = newDreamSlug()
+		if err := s.save(id, &d); err != nil {
+			return nil, err
+		}
+	}
+	return &d, nil
+}
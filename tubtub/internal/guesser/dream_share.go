@@ -0,0 +1,197 @@
+package guesser
+
+import (
+	crypto_rand "crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	dreamSharesDir = "shares"
+	// longest expiry a share can ask for
+	maxDreamShareTTL = 365 * 24 * time.Hour
+)
+
+var (
+	ErrDreamUnfinished = errors.New("dream game isn't finished yet")
+	ErrDreamExpired    = errors.New("this dream game link has expired")
+	ErrDreamNoShare    = errors.New("dream game link not found")
+)
+
+var slugEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// DreamShare is a finished board published under an unguessable slug.
+type DreamShare struct {
+	Slug      string     `json:"slug"`
+	URL       string     `json:"url"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Dream     *DreamGame `json:"dream"`
+}
+
+// newDreamSlug is 80 random bits as 16 lowercase base32 characters.
+func newDreamSlug() string {
+	var b [10]byte
+	_, _ = crypto_rand.Read(b[:])
+	return strings.ToLower(slugEncoding.EncodeToString(b[:]))
+}
+
+// dreamShareURL is the read-only page for a share.
+func dreamShareURL(slug string) string {
+	return "/gamehub/dream.html?s=" + slug
+}
+
+func (d *DreamShare) expired(now time.Time) bool {
+	return d.ExpiresAt != nil && now.After(*d.ExpiresAt)
+}
+
+// Share publishes the finished board of session id. A board has one share: sharing
+// it again keeps the slug and only changes the expiry, and created reports whether
+// the slug is new. A ttl of 0 never expires.
+func (s *DreamStore) Share(id string, ttl time.Duration) (sh *DreamShare, created bool, err error) {
+	if ttl < 0 || ttl > maxDreamShareTTL {
+		return nil, false, fmt.Errorf("expiry must be between 0 and %d days", int(maxDreamShareTTL.Hours()/24))
+	}
+	view, err := s.Get(id)
+	if err != nil {
+		return nil, false, err
+	}
+	if view.Dream == nil {
+		return nil, false, ErrDreamUnfinished
+	}
+
+	now := time.Now().UTC()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, old := range s.shares {
+		if old.Dream != nil && old.Dream.ID == view.Dream.ID && !old.expired(now) {
+			sh = old
+		}
+	}
+	if sh == nil {
+		created = true
+		sh = &DreamShare{CreatedAt: now, Dream: view.Dream}
+		for sh.Slug == "" || s.shares[sh.Slug] != nil {
+			sh.Slug = newDreamSlug()
+		}
+		sh.URL = dreamShareURL(sh.Slug)
+	}
+	sh.ExpiresAt = nil
+	if ttl > 0 {
+		at := now.Add(ttl)
+		sh.ExpiresAt = &at
+	}
+
+	if err := s.saveShare(sh); err != nil {
+		return nil, false, err
+	}
+	s.shares[sh.Slug] = sh
+	return sh, created, nil
+}
+
+func (s *DreamStore) saveShare(sh *DreamShare) error {
+	path, ok := s.pathFor(dreamSharesDir, sh.Slug)
+	if !ok {
+		return nil
+	}
+	data, err := json.MarshalIndent(sh, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("save share: %w", err)
+	}
+	return nil
+}
+
+// loadShares reads every saved share so the feeds can list them.
+func (s *DreamStore) loadShares() {
+	paths, _ := filepath.Glob(filepath.Join(s.dir, dreamSharesDir, "*.json"))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("dream share: %v\n", err)
+			continue
+		}
+		sh := &DreamShare{}
+		if err := json.Unmarshal(data, sh); err != nil || sh.Slug == "" {
+			log.Printf("dream share %s: unreadable, skipped\n", filepath.Base(path))
+			continue
+		}
+		if sh.Dream != nil {
+			s.migrateShare(sh)
+		}
+		s.shares[sh.Slug] = sh
+	}
+}
+
+// migrateShare replaces a session ID left in an older share with the board's public ID.
+func (s *DreamStore) migrateShare(sh *DreamShare) {
+	path, ok := s.pathFor("", sh.Dream.ID)
+	if !ok {
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+	board, err := s.load(sh.Dream.ID)
+	if err != nil {
+		log.Printf("dream share %s: %v\n", sh.Slug, err)
+		return
+	}
+	sh.Dream.ID = board.ID
+	if err := s.saveShare(sh); err != nil {
+		log.Printf("dream share %s: %v\n", sh.Slug, err)
+	}
+}
+
+// Shared looks a share up by slug. Expired shares are deleted on first access.
+func (s *DreamStore) Shared(slug string) (*DreamShare, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sh := s.shares[slug]
+	path, onDisk := s.pathFor(dreamSharesDir, slug)
+	if sh == nil && onDisk {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrDreamNoShare
+		}
+		if err != nil {
+			return nil, err
+		}
+		sh = &DreamShare{}
+		if err := json.Unmarshal(data, sh); err != nil {
+			return nil, fmt.Errorf("decode share %s: %w", slug, err)
+		}
+		s.shares[slug] = sh
+	}
+	if sh == nil {
+		return nil, ErrDreamNoShare
+	}
+
+	if sh.expired(time.Now()) {
+		delete(s.shares, slug)
+		if _, ok := s.votes[slug]; ok {
+			delete(s.votes, slug)
+			if err := s.saveVotes(); err != nil {
+				log.Printf("dream votes: %v\n", err)
+			}
+		}
+		if onDisk {
+			os.Remove(path)
+		}
+		return nil, ErrDreamExpired
+	}
+	return sh, nil
+}
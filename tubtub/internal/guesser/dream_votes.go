@@ -0,0 +1,233 @@
+package guesser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"tubtub/internal/webutil"
+)
+
+const (
+	dreamVotesFile = "votes.json"
+
+	defaultDreamFeedLimit = 20
+	maxDreamFeedLimit     = 100
+)
+
+var (
+	ErrVotePlayer      = errors.New("missing or invalid player id")
+	ErrVoteRateLimited = errors.New("too many votes, slow down")
+	ErrVoteAddrUsed    = errors.New("already voted from this address")
+)
+
+// anonymous player IDs are generated by the browser (a UUID in practice)
+var playerIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{8,64}$`)
+
+// dreamVotes is one share's tally. Voters holds hashed player IDs and Addrs maps
+// each hashed voting address to the player that voted from it, so the file doesn't
+// keep anything a player could be recognised by. Player IDs are free to make up,
+// so an address only gets one vote per share.
+type dreamVotes struct {
+	Votes  int               `json:"votes"`
+	Voters map[string]bool   `json:"voters"`
+	Addrs  map[string]string `json:"addrs,omitempty"`
+}
+
+func voterKey(playerID string) string {
+	sum := sha256.Sum256([]byte(playerID))
+	return hex.EncodeToString(sum[:12])
+}
+
+// dreamVoteLimits caps how fast one player and one address can vote.
+type dreamVoteLimits struct {
+	player *webutil.RateLimiter
+	ip     *webutil.RateLimiter
+}
+
+func newDreamVoteLimits() dreamVoteLimits {
+	return dreamVoteLimits{
+		player: webutil.NewRateLimiter(10, time.Minute),
+		ip:     webutil.NewRateLimiter(30, time.Minute),
+	}
+}
+
+// DreamVoteResult is the share's tally after a vote.
+type DreamVoteResult struct {
+	Slug         string `json:"slug"`
+	Votes        int    `json:"votes"`
+	Voted        bool   `json:"voted"`
+	AlreadyVoted bool   `json:"alreadyVoted,omitempty"`
+}
+
+// Vote upvotes a share once per player. up=false takes the player's vote back.
+func (s *DreamStore) Vote(slug, playerID, ip string, up bool) (*DreamVoteResult, error) {
+	if !playerIDPattern.MatchString(playerID) {
+		return nil, ErrVotePlayer
+	}
+	if !s.limits.ip.Allow(ip) || !s.limits.player.Allow(playerID) {
+		return nil, ErrVoteRateLimited
+	}
+	sh, err := s.Shared(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := s.votes[sh.Slug]
+	if v == nil {
+		v = &dreamVotes{Voters: map[string]bool{}}
+		s.votes[sh.Slug] = v
+	}
+	if v.Addrs == nil {
+		v.Addrs = map[string]string{}
+	}
+	key, addr := voterKey(playerID), voterKey("addr:"+ip)
+	res := &DreamVoteResult{Slug: sh.Slug, Voted: up}
+	voted := v.Voters[key]
+	if up == voted {
+		// nothing to change
+		res.AlreadyVoted = up
+		res.Votes = v.Votes
+		return res, nil
+	}
+	if up && v.Addrs[addr] != "" {
+		// another player on this address has the vote
+		return nil, ErrVoteAddrUsed
+	}
+	switch {
+	case up:
+		v.Voters[key] = true
+		v.Addrs[addr] = key
+		v.Votes++
+	default:
+		delete(v.Voters, key)
+		for a, k := range v.Addrs {
+			if k == key {
+				delete(v.Addrs, a)
+			}
+		}
+		v.Votes--
+	}
+	res.Votes = v.Votes
+
+	if err := s.saveVotes(); err != nil {
+		// the vote still counts until the next restart
+		log.Printf("dream votes: %v\n", err)
+	}
+	return res, nil
+}
+
+// FeedItem is the public view of one share with its current tally.
+func (s *DreamStore) FeedItem(sh *DreamShare) DreamFeedItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	votes := 0
+	if v := s.votes[sh.Slug]; v != nil {
+		votes = v.Votes
+	}
+	return newDreamFeedItem(sh, votes)
+}
+
+// saveVotes writes every tally. Callers hold s.mu.
+func (s *DreamStore) saveVotes() error {
+	if s.dir == "" {
+		return nil
+	}
+	data, err := json.Marshal(s.votes)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(s.dir, dreamVotesFile), data); err != nil {
+		return fmt.Errorf("save votes: %w", err)
+	}
+	return nil
+}
+
+func (s *DreamStore) loadVotes() {
+	data, err := os.ReadFile(filepath.Join(s.dir, dreamVotesFile))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("dream votes: %v\n", err)
+		}
+		return
+	}
+	if err := json.Unmarshal(data, &s.votes); err != nil {
+		log.Printf("dream votes: %v\n", err)
+	}
+}
+
+// ----------------------------
+// Feeds
+// ----------------------------
+
+// DreamFeedItem is the public view of a share with its votes, as served by
+// /api/dream/{slug} and listed on /api/dream/top and /api/dream/recent. It is
+// copied field by field so nothing added to DreamShare is published by accident.
+type DreamFeedItem struct {
+	Slug      string     `json:"slug"`
+	URL       string     `json:"url"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Dream     DreamBoard `json:"dream"`
+	Votes     int        `json:"votes"`
+}
+
+// DreamBoard is a shared board without anything that identifies its session.
+type DreamBoard struct {
+	ID         string      `json:"id"`
+	Pack       string      `json:"pack"`
+	FinishedAt time.Time   `json:"finishedAt"`
+	Slots      []DreamSlot `json:"slots"`
+}
+
+func newDreamFeedItem(sh *DreamShare, votes int) DreamFeedItem {
+	item := DreamFeedItem{Slug: sh.Slug, URL: sh.URL, CreatedAt: sh.CreatedAt, Votes: votes}
+	if sh.ExpiresAt != nil {
+		at := *sh.ExpiresAt
+		item.ExpiresAt = &at
+	}
+	if d := sh.Dream; d != nil {
+		item.Dream = DreamBoard{ID: d.ID, Pack: d.Pack, FinishedAt: d.FinishedAt, Slots: d.Slots}
+	}
+	return item
+}
+
+// Feed lists live shares, newest first or, with top, most voted first.
+func (s *DreamStore) Feed(top bool, limit int) []DreamFeedItem {
+	now := time.Now()
+	s.mu.Lock()
+	items := []DreamFeedItem{}
+	for _, sh := range s.shares {
+		if sh.expired(now) {
+			continue
+		}
+		votes := 0
+		if v := s.votes[sh.Slug]; v != nil {
+			votes = v.Votes
+		}
+		items = append(items, newDreamFeedItem(sh, votes))
+	}
+	s.mu.Unlock()
+
+	sort.Slice(items, func(a, b int) bool {
+		if top && items[a].Votes != items[b].Votes {
+			return items[a].Votes > items[b].Votes
+		}
+		return items[a].CreatedAt.After(items[b].CreatedAt)
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,78 @@
+package guesser
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// sharedDream puts a live share straight into the store.
+func sharedDream(s *DreamStore, slug string) {
+	expires := time.Now().Add(time.Hour)
+	s.shares[slug] = &DreamShare{Slug: slug, CreatedAt: time.Now(), ExpiresAt: &expires, Dream: &DreamGame{ID: "board"}}
+}
+
+func TestVoteSameAddress(t *testing.T) {
+	s := NewDreamStore(t.TempDir())
+	sharedDream(s, "abc123")
+	const ip = "203.0.113.5"
+
+	res, err := s.Vote("abc123", "player-one", ip, true)
+	if err != nil || !res.Voted || res.Votes != 1 {
+		t.Fatalf("first vote = %+v, %v", res, err)
+	}
+
+	// the same player again changes nothing
+	res, err = s.Vote("abc123", "player-one", ip, true)
+	if err != nil || !res.AlreadyVoted || res.Votes != 1 {
+		t.Fatalf("repeat vote = %+v, %v", res, err)
+	}
+
+	// a second player on the same address is refused, not told they voted
+	res, err = s.Vote("abc123", "player-two", ip, true)
+	if !errors.Is(err, ErrVoteAddrUsed) || res != nil {
+		t.Fatalf("second player = %+v, %v; want ErrVoteAddrUsed", res, err)
+	}
+
+	// another address still counts
+	res, err = s.Vote("abc123", "player-two", "198.51.100.7", true)
+	if err != nil || !res.Voted || res.Votes != 2 {
+		t.Fatalf("other address = %+v, %v", res, err)
+	}
+
+	// taking the first vote back frees the address
+	if res, err = s.Vote("abc123", "player-one", ip, false); err != nil || res.Votes != 1 {
+		t.Fatalf("unvote = %+v, %v", res, err)
+	}
+	res, err = s.Vote("abc123", "player-three", ip, true)
+	if err != nil || !res.Voted || res.Votes != 2 {
+		t.Fatalf("vote after unvote = %+v, %v", res, err)
+	}
+}
+
+func TestExpiredShareDropsSavedVotes(t *testing.T) {
+	dir := t.TempDir()
+	s := NewDreamStore(dir)
+	sharedDream(s, "abc123")
+	sharedDream(s, "def456")
+	for _, slug := range []string{"abc123", "def456"} {
+		if _, err := s.Vote(slug, "player-one", "203.0.113.5", true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	past := time.Now().Add(-time.Minute)
+	s.shares["abc123"].ExpiresAt = &past
+	if _, err := s.Shared("abc123"); !errors.Is(err, ErrDreamExpired) {
+		t.Fatalf("Shared(expired) err = %v, want ErrDreamExpired", err)
+	}
+
+	// a restart must not bring the expired share's tally back
+	s = NewDreamStore(dir)
+	if _, ok := s.votes["abc123"]; ok {
+		t.Fatal("expired share's votes were reloaded")
+	}
+	if v := s.votes["def456"]; v == nil || v.Votes != 1 {
+		t.Fatalf("live share's votes = %+v, want 1", v)
+	}
+}
@@ -0,0 +1,289 @@
+package guesser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultDuplicateThreshold is the confidence at which a pair is reported by
+// `tubtub dataset dupes`; lint only warns from DuplicateLintThreshold up.
+const (
+	DefaultDuplicateThreshold = 0.6
+	DuplicateLintThreshold    = 0.75
+)
+
+// lowest confidence for two games whose names normalize to the same thing, however
+// much their other fields disagree; above both thresholds so lint warns too
+const sameNameConfidence = 0.8
+
+// fewer shared non-empty attributes than this and the attribute score is ignored
+const minComparedAttributes = 5
+
+// words that mark a re-release rather than a different game
+var editionWords = map[string]bool{
+	"edition": true, "remastered": true, "remaster": true, "definitive": true,
+	"goty": true, "deluxe": true, "complete": true, "hd": true, "remake": true,
+	"enhanced": true, "ultimate": true, "directors": true, "cut": true,
+}
+
+// sequel numbers are compared as digits so "III" and "3" match
+var romanNumerals = map[string]string{
+	"ii": "2", "iii": "3", "iv": "4", "v": "5", "vi": "6",
+	"vii": "7", "viii": "8", "ix": "9", "x": "10",
+}
+
+// DuplicateCandidate is a pair of games that may be the same title.
+type DuplicateCandidate struct {
+	A          GameSummary `json:"a"`
+	B          GameSummary `json:"b"`
+	Confidence float64     `json:"confidence"`
+	Reasons    []string    `json:"reasons"`
+}
+
+// duplicateNameTokens is norm() plus the noise that varies between copies of the
+// same entry: punctuation, "&", a leading "the", edition words and roman numerals.
+func duplicateNameTokens(name string) []string {
+	s := strings.ReplaceAll(norm(name), "&", " and ")
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, s)
+
+	var out []string
+	for i, t := range strings.Fields(s) {
+		if (i == 0 && t == "the") || editionWords[t] {
+			continue
+		}
+		if n, ok := romanNumerals[t]; ok && i > 0 {
+			t = n
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// numberedEntries reports whether two names differ only by sequel numbers,
+// e.g. "Dark Souls II" and "Dark Souls III".
+func numberedEntries(a, b []string) bool {
+	count := map[string]int{}
+	for _, t := range a {
+		count[t]++
+	}
+	for _, t := range b {
+		count[t]--
+	}
+
+	differs := false
+	for t, n := range count {
+		if n == 0 {
+			continue
+		}
+		differs = true
+		if strings.TrimFunc(t, unicode.IsDigit) != "" {
+			return false
+		}
+	}
+	return differs
+}
+
+// editDistance counts the single-rune insertions, deletions, substitutions and
+// adjacent swaps that turn a into b, so a transposed typo ("zleda") costs one edit.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	twoBack := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], twoBack[j-2]+1)
+			}
+		}
+		twoBack, prev, cur = prev, cur, twoBack
+	}
+	return prev[len(rb)]
+}
+
+func nameSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(editDistance(a, b))/float64(longest)
+}
+
+// attributeSimilarity averages per-field agreement over the fields both games fill in:
+// Jaccard overlap for lists, case-insensitive equality for strings.
+func attributeSimilarity(a, b *Game, cols []csvColumn) (float64, int) {
+	total, n := 0.0, 0
+	for _, c := range cols {
+		switch c.Key {
+		case "id", "name", "year", "series", "imageUrl":
+			continue
+		}
+
+		switch c.Kind {
+		case KindList:
+			la, _ := rawFieldValue(a, c.Key).([]string)
+			lb, _ := rawFieldValue(b, c.Key).([]string)
+			la, lb = CleanList(la, 0), CleanList(lb, 0)
+			if len(la) == 0 || len(lb) == 0 {
+				continue
+			}
+			total += jaccard(la, lb)
+			n++
+		case KindString:
+			sa, _ := rawFieldValue(a, c.Key).(string)
+			sb, _ := rawFieldValue(b, c.Key).(string)
+			sa, sb = CleanString(sa), CleanString(sb)
+			if sa == "" || sb == "" {
+				continue
+			}
+			if strings.EqualFold(sa, sb) {
+				total++
+			}
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	return total / float64(n), n
+}
+
+func jaccard(a, b []string) float64 {
+	set := map[string]bool{}
+	for _, v := range a {
+		set[strings.ToLower(v)] = true
+	}
+	inter, union := 0, len(set)
+	for _, v := range b {
+		k := strings.ToLower(v)
+		if set[k] {
+			inter++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(inter) / float64(union)
+}
+
+// FindDuplicates scores every pair of games and returns those at or above threshold,
+// most confident first.
+//
+// The name counts for half the score, year and series for a tenth each and the
+// attribute fields for the rest. Pairs need a close name or near-identical attributes
+// to be considered at all, and numbered sequels are scored down. An identical
+// normalized name alone is enough to report a pair.
+func FindDuplicates(games []*Game, manifest *PackManifest, threshold float64) []DuplicateCandidate {
+	cols := csvColumns(manifest)
+	tokens := make([][]string, len(games))
+	keys := make([]string, len(games))
+	for i, g := range games {
+		tokens[i] = duplicateNameTokens(g.Name)
+		keys[i] = strings.Join(tokens[i], " ")
+	}
+
+	out := []DuplicateCandidate{}
+	for i := 0; i < len(games); i++ {
+		for j := i + 1; j < len(games); j++ {
+			a, b := games[i], games[j]
+			var reasons []string
+
+			name := nameSimilarity(keys[i], keys[j])
+			switch {
+			case keys[i] == keys[j]:
+				reasons = append(reasons, "same normalized name")
+			case name >= 0.8:
+				reasons = append(reasons, fmt.Sprintf("similar name (%.2f)", name))
+			}
+
+			attr, compared := attributeSimilarity(a, b, cols)
+			if compared < minComparedAttributes {
+				attr = 0
+			}
+			if name < 0.8 && attr < 0.9 {
+				continue
+			}
+			if attr >= 0.5 {
+				reasons = append(reasons, fmt.Sprintf("%.0f%% of %d attributes match", attr*100, compared))
+			}
+
+			year := 0.0
+			switch d := a.Year - b.Year; {
+			case a.Year == 0 || b.Year == 0:
+			case d == 0:
+				year = 1
+				reasons = append(reasons, "same year")
+			case d == 1 || d == -1:
+				year = 0.5
+				reasons = append(reasons, "adjacent year")
+			}
+
+			series := 0.0
+			if sa, sb := norm(CleanString(a.Series)), norm(CleanString(b.Series)); sa != "" && sa == sb {
+				series = 1
+				reasons = append(reasons, "same series")
+			}
+
+			score := 0.5*name + 0.1*year + 0.1*series + 0.3*attr
+			if keys[i] == keys[j] {
+				score = max(score, sameNameConfidence)
+			}
+			if numberedEntries(tokens[i], tokens[j]) {
+				score *= 0.5
+				reasons = append(reasons, "names differ only by sequel number")
+			}
+			if score < threshold {
+				continue
+			}
+
+			out = append(out, DuplicateCandidate{
+				A:          summarize(a),
+				B:          summarize(b),
+				Confidence: float64(int(score*100+0.5)) / 100,
+				Reasons:    reasons,
+			})
+		}
+	}
+
+	sort.SliceStable(out, func(x, y int) bool { return out[x].Confidence > out[y].Confidence })
+	return out
+}
+
+// FindDatasetDuplicates runs FindDuplicates over the dataset file at path.
+func FindDatasetDuplicates(path string, threshold float64) ([]DuplicateCandidate, error) {
+	data, err := readDataset(path)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := LoadPackManifest(manifestPathFor(path))
+	if err != nil {
+		return nil, err
+	}
+	games, _, err := parseDataset(data, manifest)
+	if err != nil {
+		return nil, err
+	}
+	return FindDuplicates(games, manifest, threshold), nil
+}
@@ -0,0 +1,172 @@
+package guesser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// groupSpec is one level of /api/explore/group: a category key plus, for numeric
+// categories, how values are bucketed.
+//
+//	platforms          list categories put a game under each entry
+//	year               one group per value
+//	year:decade        "2010s"
+//	year:5             five-year spans, "2015-2019"
+//	year:..2009,2010..2014,2015..
+//	                   custom ranges (open at either end); values outside them are dropped
+type groupSpec struct {
+	key    string
+	kind   string
+	width  int
+	ranges []string
+}
+
+func parseGroupSpec(idx *Index, s string) (*groupSpec, error) {
+	key, bucket, _ := strings.Cut(strings.TrimSpace(s), ":")
+	def, ok := idx.Registry.Def(key)
+	if !ok {
+		return nil, fmt.Errorf("cannot group by %q: not a category of this pack", key)
+	}
+	spec := &groupSpec{key: key, kind: def.Kind}
+
+	switch {
+	case bucket == "":
+	case def.Kind != KindInt:
+		return nil, fmt.Errorf("cannot bucket %s: only numeric categories have buckets", key)
+	case bucket == "decade":
+		spec.width = 10
+	case strings.Contains(bucket, ".."):
+		for _, r := range strings.Split(bucket, ",") {
+			r = strings.TrimSpace(r)
+			lo, hi, _ := strings.Cut(r, "..")
+			for _, end := range []string{lo, hi} {
+				if _, err := strconv.Atoi(end); end != "" && err != nil {
+					return nil, fmt.Errorf("invalid range %q", r)
+				}
+			}
+			spec.ranges = append(spec.ranges, r)
+		}
+	default:
+		n, err := strconv.Atoi(bucket)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid bucket %q (use decade, a span like 5 or ranges like ..2009,2010..)", bucket)
+		}
+		spec.width = n
+	}
+	return spec, nil
+}
+
+func (s *groupSpec) keysOf(idx *Index, g *Game) []string {
+	if s.kind != KindInt {
+		return idx.Registry.Values(g, s.key)
+	}
+	v, ok := idx.Registry.Value(g, s.key).(int)
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case len(s.ranges) > 0:
+		for _, r := range s.ranges {
+			if intMatches(v, r) {
+				return []string{r}
+			}
+		}
+		return nil
+	case s.width == 10:
+		return []string{fmt.Sprintf("%ds", v-v%10)}
+	case s.width > 1:
+		lo := v - v%s.width
+		return []string{fmt.Sprintf("%d-%d", lo, lo+s.width-1)}
+	}
+	return []string{strconv.Itoa(v)}
+}
+
+// compare orders custom ranges as written and everything else naturally.
+func (s *groupSpec) compare(a, b string) bool {
+	if len(s.ranges) > 0 {
+		pos := map[string]int{}
+		for i, r := range s.ranges {
+			pos[r] = i
+		}
+		return pos[a] < pos[b]
+	}
+	if s.kind == KindInt {
+		return byLeadingNumber(a, b)
+	}
+	return byText(a, b)
+}
+
+// ExploreGroupHandler groups the ?pack= games by any category:
+//
+//	/api/explore/group?by=platforms
+//	/api/explore/group?by=platforms&then=year:decade
+//
+// With then= each outer group holds inner groups, and paging applies to the inner
+// groups' games. Filters, sort, paging and fields work as on the other explore endpoints.
+func ExploreGroupHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+		idx := cat.Index()
+
+		by := r.URL.Query().Get("by")
+		if by == "" {
+			http.Error(w, "missing by", 400)
+			return
+		}
+		outer, err := parseGroupSpec(idx, by)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		var inner *groupSpec
+		if then := r.URL.Query().Get("then"); then != "" {
+			if inner, err = parseGroupSpec(idx, then); err != nil {
+				http.Error(w, err.Error(), 400)
+				return
+			}
+		}
+
+		views, err := newGameViews(r, idx)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		q, err := parseExploreQuery(r, idx)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+
+		outerKeys := func(g *Game) []string { return outer.keysOf(idx, g) }
+		if inner == nil {
+			groups, total := groupGames(idx, q, views, outerKeys, outer.compare)
+			json.NewEncoder(w).Encode(ExploreResponse{By: by, Total: total, Groups: groups})
+			return
+		}
+
+		kept := q.filter(idx.Games)
+		keys, buckets := bucketGames(kept, outerKeys, outer.compare)
+		groups := []ExploreGroup{}
+		for _, k := range keys {
+			if q.group != "" && !strings.EqualFold(k, q.group) {
+				continue
+			}
+			innerKeys, innerBuckets := bucketGames(buckets[k], func(g *Game) []string { return inner.keysOf(idx, g) }, inner.compare)
+			grp := ExploreGroup{Key: k, Count: len(buckets[k]), Groups: []ExploreGroup{}}
+			for _, ik := range innerKeys {
+				grp.Groups = append(grp.Groups, q.page(ik, innerBuckets[ik], views))
+			}
+			groups = append(groups, grp)
+		}
+
+		json.NewEncoder(w).Encode(ExploreResponse{By: by + "," + r.URL.Query().Get("then"), Total: len(kept), Groups: groups})
+	})
+}
@@ -3,62 +3,61 @@ package guesser
 import (
 	"encoding/json"
 	"net/http"
-	"strings"
+	"strconv"
 )
 
-// Group by year
-func ExploreByYearHandler(idx *Index) http.Handler {
+// exploreHandler serves one grouping of the ?pack= games. Every explore endpoint takes
+//
+//	?<field>=value      filter on any record field (repeat for OR; ints take lo..hi)
+//	?sort=name|year|id  order inside each group, "-" prefix for descending
+//	?limit=&page=       or ?limit=&cursor= to page through each group
+//	?group=             return only that group
+//	?fields=            projection, see fieldsParam
+func exploreHandler(lib *Library, by string, keysOf func(idx *Index, g *Game) []string, compareKeys func(a, b string) bool) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-
-		out := map[int][]*Game{}
-
-		for _, g := range idx.Games {
-			if g.Year > 0 {
-				out[g.Year] = append(out[g.Year], g)
-			}
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+		idx := cat.Index()
+		views, err := newGameViews(r, idx)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		q, err := parseExploreQuery(r, idx)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
 		}
 
-		json.NewEncoder(w).Encode(out)
+		groups, total := groupGames(idx, q, views, func(g *Game) []string { return keysOf(idx, g) }, compareKeys)
+
+		json.NewEncoder(w).Encode(ExploreResponse{By: by, Total: total, Groups: groups})
 	})
 }
 
-// Group by platform
-func ExploreByPlatformHandler(idx *Index) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-
-		out := map[string][]*Game{}
-
-		for _, g := range idx.Games {
-			for _, p := range g.Platforms {
-				if strings.TrimSpace(p) == "" {
-					continue
-				}
-				out[p] = append(out[p], g)
-			}
+// Group by year
+func ExploreByYearHandler(lib *Library) http.Handler {
+	return exploreHandler(lib, "year", func(idx *Index, g *Game) []string {
+		if y, ok := idx.Registry.Value(g, "year").(int); ok {
+			return []string{strconv.Itoa(y)}
 		}
+		return nil
+	}, byNumber)
+}
 
-		json.NewEncoder(w).Encode(out)
-	})
+// Group by platform
+func ExploreByPlatformHandler(lib *Library) http.Handler {
+	return exploreHandler(lib, "platform", func(idx *Index, g *Game) []string {
+		return idx.Registry.Values(g, "platforms")
+	}, byText)
 }
 
 // Group by genre
-func ExploreByGenreHandler(idx *Index) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-
-		out := map[string][]*Game{}
-
-		for _, g := range idx.Games {
-			if v := strings.TrimSpace(g.PrimaryGenre); v != "" {
-				out[v] = append(out[v], g)
-			}
-			for _, gen := range g.SubGenres {
-				if strings.TrimSpace(gen) == "" {
-					continue
-				}
-				out[gen] = append(out[gen], g)
-			}
-		}
-
-		json.NewEncoder(w).Encode(out)
-	})
+func ExploreByGenreHandler(lib *Library) http.Handler {
+	return exploreHandler(lib, "genre", func(idx *Index, g *Game) []string {
+		return append(idx.Registry.Values(g, "primary_genre"), idx.Registry.Values(g, "sub_genres")...)
+	}, byText)
 }
@@ -0,0 +1,322 @@
+package guesser
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// query parameters the explore endpoints reserve; every other parameter must name
+// a record field and is a filter
+var exploreReserved = map[string]bool{
+	"pack": true, "fields": true, "sort": true, "limit": true, "page": true, "cursor": true, "group": true,
+	"by": true, "then": true,
+}
+
+// exploreFilter keeps games whose field matches one of the wanted values.
+// Strings match case-insensitively, lists match if any entry does and ints take
+// either a value or a lo..hi range (either end may be left open).
+type exploreFilter struct {
+	field  string
+	values []string
+}
+
+func (f exploreFilter) match(g *Game) bool {
+	raw := cleanValue(rawFieldValue(g, f.field))
+	for _, want := range f.values {
+		switch v := raw.(type) {
+		case string:
+			if strings.EqualFold(v, want) {
+				return true
+			}
+		case []string:
+			for _, item := range v {
+				if strings.EqualFold(item, want) {
+					return true
+				}
+			}
+		case int:
+			if intMatches(v, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func intMatches(v int, want string) bool {
+	lo, hi, isRange := strings.Cut(want, "..")
+	if !isRange {
+		n, err := strconv.Atoi(strings.TrimSpace(want))
+		return err == nil && n == v
+	}
+	if lo = strings.TrimSpace(lo); lo != "" {
+		if n, err := strconv.Atoi(lo); err != nil || v < n {
+			return false
+		}
+	}
+	if hi = strings.TrimSpace(hi); hi != "" {
+		if n, err := strconv.Atoi(hi); err != nil || v > n {
+			return false
+		}
+	}
+	return true
+}
+
+// exploreQuery is the filtering, sorting and paging asked for on an explore request.
+type exploreQuery struct {
+	filters []exploreFilter
+	sortKey string
+	desc    bool
+	limit   int
+	offset  int
+	group   string
+}
+
+func parseExploreQuery(r *http.Request, idx *Index) (*exploreQuery, error) {
+	q := r.URL.Query()
+	eq := &exploreQuery{sortKey: "name"}
+
+	known := map[string]bool{}
+	for _, c := range csvColumns(idx.Manifest) {
+		known[c.Key] = true
+	}
+	var keys []string
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if exploreReserved[k] {
+			continue
+		}
+		// a typo would otherwise quietly return everything
+		if !known[k] {
+			return nil, fmt.Errorf("unknown filter %q", k)
+		}
+		eq.filters = append(eq.filters, exploreFilter{field: k, values: q[k]})
+	}
+
+	if s := q.Get("sort"); s != "" {
+		eq.desc = strings.HasPrefix(s, "-")
+		eq.sortKey = strings.TrimPrefix(s, "-")
+		if eq.sortKey != "name" && eq.sortKey != "year" && eq.sortKey != "id" {
+			return nil, fmt.Errorf("cannot sort by %q (use name, year or id)", eq.sortKey)
+		}
+	}
+
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid limit %q", s)
+		}
+		eq.limit = n
+	}
+	switch {
+	case q.Get("cursor") != "":
+		n, err := decodeCursor(q.Get("cursor"))
+		if err != nil {
+			return nil, err
+		}
+		eq.offset = n
+	case q.Get("page") != "":
+		n, err := strconv.Atoi(q.Get("page"))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid page %q", q.Get("page"))
+		}
+		eq.offset = (n - 1) * eq.limit
+	}
+	eq.group = q.Get("group")
+
+	return eq, nil
+}
+
+// cursors are opaque to clients but are just the offset into the sorted group
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("o:" + strconv.Itoa(offset)))
+}
+
+func decodeCursor(s string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	off, ok := strings.CutPrefix(string(raw), "o:")
+	n, nerr := strconv.Atoi(off)
+	if err != nil || !ok || nerr != nil || n < 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return n, nil
+}
+
+func (q *exploreQuery) keep(g *Game) bool {
+	for _, f := range q.filters {
+		if !f.match(g) {
+			return false
+		}
+	}
+	return true
+}
+
+func (q *exploreQuery) filter(games []*Game) []*Game {
+	var kept []*Game
+	for _, g := range games {
+		if q.keep(g) {
+			kept = append(kept, g)
+		}
+	}
+	return kept
+}
+
+func (q *exploreQuery) sortGames(games []*Game) {
+	sort.SliceStable(games, func(a, b int) bool {
+		ga, gb := games[a], games[b]
+		var less, equal bool
+		switch q.sortKey {
+		case "year":
+			less, equal = ga.Year < gb.Year, ga.Year == gb.Year
+		case "id":
+			less, equal = ga.ID < gb.ID, ga.ID == gb.ID
+		default:
+			na, nb := strings.ToLower(ga.Name), strings.ToLower(gb.Name)
+			less, equal = na < nb, na == nb
+		}
+		if equal {
+			return ga.ID < gb.ID
+		}
+		return less != q.desc
+	})
+}
+
+// ----------------------------
+// Groups
+// ----------------------------
+
+// ExploreGroup is one group of an explore response. Games is the requested page;
+// Count is the size of the whole group after filtering. With two-level grouping
+// the outer groups carry Groups instead of Games.
+type ExploreGroup struct {
+	Key        string                   `json:"key"`
+	Count      int                      `json:"count"`
+	Games      []map[string]interface{} `json:"games"`
+	NextCursor string                   `json:"nextCursor,omitempty"`
+	Groups     []ExploreGroup           `json:"groups,omitempty"`
+}
+
+// MarshalJSON always writes games for a leaf group, even when the page is past the
+// end, and leaves it out of outer groups.
+func (g ExploreGroup) MarshalJSON() ([]byte, error) {
+	if g.Groups != nil {
+		return json.Marshal(struct {
+			Key    string         `json:"key"`
+			Count  int            `json:"count"`
+			Groups []ExploreGroup `json:"groups"`
+		}{g.Key, g.Count, g.Groups})
+	}
+	type plain ExploreGroup
+	if g.Games == nil {
+		g.Games = []map[string]interface{}{}
+	}
+	return json.Marshal(plain(g))
+}
+
+type ExploreResponse struct {
+	By     string         `json:"by"`
+	Total  int            `json:"total"`
+	Groups []ExploreGroup `json:"groups"`
+}
+
+// groupGames buckets the filtered games by keysOf and pages each group.
+// Groups come back ordered by compareKeys.
+func groupGames(idx *Index, q *exploreQuery, views *gameViews, keysOf func(*Game) []string, compareKeys func(a, b string) bool) ([]ExploreGroup, int) {
+	kept := q.filter(idx.Games)
+	keys, buckets := bucketGames(kept, keysOf, compareKeys)
+	groups := make([]ExploreGroup, 0, len(keys))
+	for _, k := range keys {
+		if q.group == "" || strings.EqualFold(k, q.group) {
+			groups = append(groups, q.page(k, buckets[k], views))
+		}
+	}
+	return groups, len(kept)
+}
+
+// bucketGames puts each game under every key it has (once per key) and returns
+// the keys in order.
+func bucketGames(games []*Game, keysOf func(*Game) []string, compareKeys func(a, b string) bool) ([]string, map[string][]*Game) {
+	buckets := map[string][]*Game{}
+	for _, g := range games {
+		seen := map[string]bool{}
+		for _, k := range keysOf(g) {
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			buckets[k] = append(buckets[k], g)
+		}
+	}
+
+	keys := make([]string, 0, len(buckets))
+	for k := range buckets {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(a, b int) bool { return compareKeys(keys[a], keys[b]) })
+	return keys, buckets
+}
+
+// page sorts one group's games and cuts out the requested page.
+func (q *exploreQuery) page(key string, games []*Game, views *gameViews) ExploreGroup {
+	q.sortGames(games)
+
+	grp := ExploreGroup{Key: key, Count: len(games), Games: []map[string]interface{}{}}
+	page := games
+	if q.offset < len(page) {
+		page = page[q.offset:]
+	} else {
+		page = nil
+	}
+	if q.limit > 0 && len(page) > q.limit {
+		page = page[:q.limit]
+		grp.NextCursor = encodeCursor(q.offset + q.limit)
+	}
+	for _, g := range page {
+		grp.Games = append(grp.Games, views.of(g))
+	}
+	return grp
+}
+
+func byText(a, b string) bool {
+	la, lb := strings.ToLower(a), strings.ToLower(b)
+	if la != lb {
+		return la < lb
+	}
+	return a < b
+}
+
+func byNumber(a, b string) bool {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	if errA != nil || errB != nil {
+		return byText(a, b)
+	}
+	return na < nb
+}
+
+// byLeadingNumber orders keys like "2010s" or "2015-2019" by their first number.
+func byLeadingNumber(a, b string) bool {
+	na, okA := leadingNumber(a)
+	nb, okB := leadingNumber(b)
+	if !okA || !okB || na == nb {
+		return byText(a, b)
+	}
+	return na < nb
+}
+
+func leadingNumber(s string) (int, bool) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	n, err := strconv.Atoi(s[:i])
+	return n, err == nil
+}
@@ -0,0 +1,155 @@
+package guesser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// compactFields is what list endpoints return per game unless ?fields= asks for more.
+var compactFields = []string{"id", "name", "year", "primary_genre", "imageUrl"}
+
+// cleanValue strips placeholders the way clues do, keeping the field's shape:
+// strings become "" and lists [] rather than disappearing.
+func cleanValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return CleanString(v)
+	case []string:
+		if l := CleanList(v, 0); l != nil {
+			return l
+		}
+		return []string{}
+	}
+	return v
+}
+
+// CleanRecord is every field of the game, built-in and pack-declared, with
+// placeholders stripped.
+func (i *Index) CleanRecord(g *Game) map[string]interface{} {
+	out := map[string]interface{}{}
+	for _, c := range csvColumns(i.Manifest) {
+		out[c.Key] = cleanValue(rawFieldValue(g, c.Key))
+	}
+	return out
+}
+
+// Project returns the named fields of the cleaned record; the id is always included.
+func (i *Index) Project(g *Game, fields []string) map[string]interface{} {
+	out := map[string]interface{}{"id": g.ID}
+	for _, f := range fields {
+		out[f] = cleanValue(rawFieldValue(g, f))
+	}
+	return out
+}
+
+// fieldsParam reads ?fields= as a comma-separated list of record fields.
+// Missing means compactFields and "all" means the full record (nil).
+func fieldsParam(r *http.Request, idx *Index) ([]string, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get("fields"))
+	switch raw {
+	case "":
+		return compactFields, nil
+	case "all":
+		return nil, nil
+	}
+
+	known := map[string]bool{}
+	for _, c := range csvColumns(idx.Manifest) {
+		known[c.Key] = true
+	}
+	var fields []string
+	for _, f := range strings.Split(raw, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !known[f] {
+			return nil, fmt.Errorf("unknown field %q", f)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// gameViews projects games for list responses, building each game's view once
+// since the same game shows up under several groups.
+type gameViews struct {
+	idx    *Index
+	fields []string
+	cache  map[int]map[string]interface{}
+}
+
+func newGameViews(r *http.Request, idx *Index) (*gameViews, error) {
+	fields, err := fieldsParam(r, idx)
+	if err != nil {
+		return nil, err
+	}
+	return &gameViews{idx: idx, fields: fields, cache: map[int]map[string]interface{}{}}, nil
+}
+
+func (v *gameViews) of(g *Game) map[string]interface{} {
+	if m, ok := v.cache[g.ID]; ok {
+		return m
+	}
+	var m map[string]interface{}
+	if v.fields == nil {
+		m = v.idx.CleanRecord(g)
+	} else {
+		m = v.idx.Project(g, v.fields)
+	}
+	v.cache[g.ID] = m
+	return m
+}
+
+// ----------------------------
+// API: single game
+// ----------------------------
+
+// GamesHandler serves /api/games/{id} (the cleaned record) and
+// /api/games/{id}/similar for the ?pack= dataset.
+func GamesHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+		idx := cat.Index()
+
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/games"), "/"), "/")
+		id, err := strconv.Atoi(parts[0])
+		if err != nil {
+			http.Error(w, "invalid game id", 400)
+			return
+		}
+		g := idx.GameByID(id)
+		if g == nil {
+			http.Error(w, "game not found", 404)
+			return
+		}
+
+		if len(parts) == 1 {
+			json.NewEncoder(w).Encode(struct {
+				Game map[string]interface{} `json:"game"`
+			}{Game: idx.CleanRecord(g)})
+			return
+		}
+		if len(parts) != 2 || parts[1] != "similar" {
+			http.NotFound(w, r)
+			return
+		}
+
+		limit := defaultSimilarLimit
+		if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 {
+			limit = min(n, similarNeighbours)
+		}
+
+		json.NewEncoder(w).Encode(struct {
+			Game    GameSummary   `json:"game"`
+			Similar []SimilarGame `json:"similar"`
+		}{Game: summarize(g), Similar: idx.Similar(id, limit)})
+	})
+}
@@ -6,9 +6,8 @@ import (
 	"strings"
 )
 
-func GuessCategoriesHandler(idx *Index, store *SessionStore) http.Handler {
+func GuessCategoriesHandler(lib *Library, store *SessionStore) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-
 		sid := strings.TrimSpace(r.URL.Query().Get("sessionId"))
 		sess, err := store.GetSession(sid)
 		if err != nil {
@@ -16,13 +15,13 @@ func GuessCategoriesHandler(idx *Index, store *SessionStore) http.Handler {
 			return
 		}
 
-		game := idx.GameByID(sess.MysteryGameID)
+		game := sess.MysteryGame(lib)
 		if game == nil {
 			http.Error(w, "missing game", 500)
 			return
 		}
 
-		cats := RandomCategories(game, sess.UsedCategories)
+		cats := RandomCategories(sess.PackIndex(lib).Registry, game, sess.UsedCategories)
 
 		json.NewEncoder(w).Encode(struct {
 			Categories    []string `json:"categories"`
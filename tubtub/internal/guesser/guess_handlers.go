@@ -19,19 +19,24 @@ type GuessStartResponse struct {
 
 const defaultBlurDataURI = "data:image/gif;base64,R0lGODlhAQABAIAAAAAAAP///ywAAAAAAQABAAACAUwAOw=="
 
-func GuessStartHandler(idx *Index, store *SessionStore) http.Handler {
+func GuessStartHandler(lib *Library, store *SessionStore) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-
 		log.Println("GuessStartHandler HIT")
 
-		sess, err := store.CreateSession()
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+
+		sess, err := store.CreateSession(cat)
 		if err != nil {
 			http.Error(w, "failed to start", 500)
 			return
 		}
 		log.Printf("GuessStart session=%s game=%d\n", sess.ID, sess.MysteryGameID)
 
-		game := idx.GameByID(sess.MysteryGameID)
+		game := sess.MysteryGame(lib)
 		if game == nil {
 			http.Error(w, "missing game", 500)
 			return
@@ -18,9 +18,8 @@ type GuessRevealResponse struct {
 	RevealedCount  int         `json:"revealedCount"`
 }
 
-func GuessRevealHandler(idx *Index, store *SessionStore) http.Handler {
+func GuessRevealHandler(lib *Library, store *SessionStore) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-
 		var req GuessRevealRequest
 
 		// ❗ Stop silently ignoring decode errors
@@ -58,11 +57,12 @@ func GuessRevealHandler(idx *Index, store *SessionStore) http.Handler {
 				return Err("no more reveals")
 			}
 
-			game := idx.GameByID(sess.MysteryGameID)
+			game := sess.MysteryGame(lib)
 			if game == nil {
 				return Err("missing game")
 			}
-			val := ExtractCategoryValue(game, req.Category)
+			reg := sess.PackIndex(lib).Registry
+			val := reg.Value(game, req.Category)
 			if val == nil {
 				return Err("no data")
 			}
@@ -70,7 +70,7 @@ func GuessRevealHandler(idx *Index, store *SessionStore) http.Handler {
 			sess.UsedCategories[req.Category] = true
 			sess.RevealedCount++
 
-			next := RandomCategories(game, sess.UsedCategories)
+			next := RandomCategories(reg, game, sess.UsedCategories)
 
 			out = GuessRevealResponse{
 				Category:       req.Category,
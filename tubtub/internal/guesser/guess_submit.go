@@ -18,18 +18,20 @@ type GuessSubmitResponse struct {
 	Game    GameSummary `json:"game"`
 }
 
+// characters norm() drops when comparing names
+const normDropped = "™®:-,."
+
 func norm(s string) string {
-	s = strings.ToLower(strings.TrimSpace(s))
-	replace := []string{"™", "", "®", "", ":", "", "-", "", ",", "", ".", ""}
-	for i := 0; i < len(replace); i += 2 {
-		s = strings.ReplaceAll(s, replace[i], replace[i+1])
-	}
-	return s
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(normDropped, r) {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(s)))
 }
 
-func GuessSubmitHandler(idx *Index, store *SessionStore) http.Handler {
+func GuessSubmitHandler(lib *Library, store *SessionStore) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-
 		sid := strings.TrimPrefix(r.URL.Path, "/api/guess/submit/")
 		if sid == "" {
 			sid = r.URL.Query().Get("sessionId")
@@ -42,7 +44,7 @@ func GuessSubmitHandler(idx *Index, store *SessionStore) http.Handler {
 
 		err := store.WithSession(sid, func(sess *Session) error {
 
-			game := idx.GameByID(sess.MysteryGameID)
+			game := sess.MysteryGame(lib)
 			if game == nil {
 				return Err("missing game")
 			}
@@ -7,37 +7,39 @@ import (
 )
 
 type GuessSuggestResponse struct {
-	Names []string `json:"names"`
+	Names   []string       `json:"names"`
+	Matches []SuggestMatch `json:"matches,omitempty"`
 }
 
-// GuessSuggestHandler returns up to 15 game names matching the query (case-insensitive substring).
-func GuessSuggestHandler(idx *Index) http.Handler {
+// GuessSuggestHandler returns up to 15 game names for the query, ranked by
+// suggestIndex (name prefix, word prefix, substring, then typos), with highlights.
+func GuessSuggestHandler(lib *Library) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		q := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+		idx := cat.Index()
+		q := strings.TrimSpace(r.URL.Query().Get("q"))
 		max := 15
 
-		var out []string
+		out := []string{}
 
 		// fast path: if no query, return a few random-ish names from the front of the list
 		if q == "" {
 			for i := 0; i < len(idx.Games) && len(out) < max; i++ {
 				out = append(out, idx.Games[i].Name)
 			}
-		} else {
-			for _, g := range idx.Games {
-				name := strings.TrimSpace(g.Name)
-				if name == "" {
-					continue
-				}
-				if strings.Contains(strings.ToLower(name), q) {
-					out = append(out, name)
-					if len(out) >= max {
-						break
-					}
-				}
-			}
+			json.NewEncoder(w).Encode(GuessSuggestResponse{Names: out})
+			return
+		}
+
+		matches := idx.suggest.suggest(q, max)
+		for _, m := range matches {
+			out = append(out, m.Name)
 		}
 
-		json.NewEncoder(w).Encode(GuessSuggestResponse{Names: out})
+		json.NewEncoder(w).Encode(GuessSuggestResponse{Names: out, Matches: matches})
 	})
 }
@@ -13,8 +13,14 @@ type GuessTickerItem struct {
 }
 
 // GuessTickerHandler returns a small shuffled list of games with images for front-end animations.
-func GuessTickerHandler(idx *Index) http.Handler {
+func GuessTickerHandler(lib *Library) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+		idx := cat.Index()
 		limit := 40
 		var items []GuessTickerItem
 
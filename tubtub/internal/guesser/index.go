@@ -1,37 +1,100 @@
 package guesser
 
 import (
-	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 )
 
 type Index struct {
 	Games []*Game
 	byID  map[int]*Game
+
+	// Report holds the validation issues found when the dataset was loaded.
+	Report *ValidationReport
+	// Unmapped lists values that were not found in the field vocabularies.
+	Unmapped []VocabMiss
+	// Manifest is the pack.json this dataset was loaded with.
+	Manifest *PackManifest
+	// neighbours holds each game's most similar games, computed at load.
+	neighbours map[int][]SimilarGame
+	// search is the inverted index behind /api/search.
+	search *searchIndex
+	// suggest ranks name completions for the guess input.
+	suggest *suggestIndex
+	// Registry holds the pack's clue categories.
+	Registry *CategoryRegistry
 }
 
-func LoadDataset(path string) (*Index, error) {
-	f, err := os.Open(path)
+func readDataset(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("open dataset: %w", err)
 	}
-	defer f.Close()
+	return data, nil
+}
+
+// InvalidDatasetError is returned when a dataset decodes but fails validation.
+type InvalidDatasetError struct {
+	Path   string
+	Report *ValidationReport
+}
+
+func (e *InvalidDatasetError) Error() string {
+	return fmt.Sprintf("dataset %s has %d error(s); run `tubtub dataset lint` for details", e.Path, e.Report.ErrorCount())
+}
+
+func LoadDataset(path string) (*Index, error) {
+	data, err := readDataset(path)
+	if err != nil {
+		return nil, err
+	}
+	return loadDatasetBytes(path, data)
+}
+
+// loadDatasetBytes builds an Index from dataset contents; path locates the pack's
+// manifest and vocabulary.
+func loadDatasetBytes(path string, data []byte) (*Index, error) {
+	manifest, err := LoadPackManifest(manifestPathFor(path))
+	if err != nil {
+		return nil, err
+	}
+
+	raw, report, err := parseDataset(data, manifest)
+	if err != nil {
+		return nil, err
+	}
+	if report.HasErrors() {
+		return nil, &InvalidDatasetError{Path: path, Report: report}
+	}
+	if n := report.WarningCount(); n > 0 {
+		log.Printf("dataset %s loaded with %d warning(s)\n", path, n)
+	}
 
-	var raw []*Game
-	dec := json.NewDecoder(f)
-	if err := dec.Decode(&raw); err != nil {
-		return nil, fmt.Errorf("decode dataset: %w", err)
+	vocab, err := LoadVocabulary(vocabularyPathFor(path), manifest)
+	if err != nil {
+		return nil, err
+	}
+	unmapped := vocab.Normalize(raw)
+	if n := len(unmapped); n > 0 {
+		log.Printf("dataset %s has %d value(s) outside the vocabulary\n", path, n)
 	}
 
 	idx := &Index{
-		Games: raw,
-		byID:  make(map[int]*Game),
+		Games:    raw,
+		byID:     make(map[int]*Game),
+		Report:   report,
+		Unmapped: unmapped,
+		Manifest: manifest,
+		Registry: newCategoryRegistry(manifest.Categories),
 	}
 
 	for _, g := range raw {
 		idx.byID[g.ID] = g
 	}
+	idx.neighbours = buildNeighbours(idx.Registry, raw)
+	idx.search = buildSearchIndex(idx.Registry, raw)
+	idx.suggest = buildSuggestIndex(raw)
 
 	return idx, nil
 }
@@ -43,3 +106,8 @@ func (i *Index) GameByID(id int) *Game {
 func (i *Index) Size() int {
 	return len(i.Games)
 }
+
+// Categories lists the clue category keys this pack offers.
+func (i *Index) Categories() []string {
+	return i.Registry.Keys()
+}
@@ -0,0 +1,210 @@
+package guesser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	packManifestFile = "pack.json"
+	packDatasetFile  = "dataset.json"
+)
+
+// value kinds a pack can declare for its own record fields
+const (
+	KindString = "string"
+	KindList   = "list"
+	KindInt    = "int"
+)
+
+// PackManifest is the optional pack.json next to a pack's dataset.json.
+//
+//	{
+//	  "label": "Movies",
+//	  "categories": [
+//	    "year",
+//	    {"key": "director", "label": "Director", "kind": "string", "strength": 3},
+//	    {"key": "genres", "kind": "list", "listCap": 4}
+//	  ],
+//	  "fields": {"poster": "string"}
+//	}
+//
+// Built-in categories can be listed by key. Custom categories declare their kind and
+// are read from the record into Game.Extra, as are the non-clue keys in fields.
+// An empty category list means every built-in category.
+type PackManifest struct {
+	Label      string            `json:"label"`
+	Categories []CategoryDef     `json:"categories"`
+	Fields     map[string]string `json:"fields"`
+}
+
+func manifestPathFor(datasetPath string) string {
+	return filepath.Join(filepath.Dir(datasetPath), packManifestFile)
+}
+
+// LoadPackManifest reads pack.json. A missing file yields the built-in game categories.
+func LoadPackManifest(path string) (*PackManifest, error) {
+	m := &PackManifest{}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("open pack manifest: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, m); err != nil {
+			return nil, fmt.Errorf("decode pack manifest: %w", err)
+		}
+	}
+
+	if m.Fields == nil {
+		m.Fields = map[string]string{}
+	}
+	for key, kind := range m.Fields {
+		if _, ok := gameJSONFields[key]; ok {
+			return nil, fmt.Errorf("pack manifest: field %q is already a Game field", key)
+		}
+		switch kind {
+		case KindString, KindList, KindInt:
+		default:
+			return nil, fmt.Errorf("pack manifest: field %q has unknown kind %q", key, kind)
+		}
+	}
+
+	if m.Categories, err = resolveCategoryDefs(m.Categories); err != nil {
+		return nil, fmt.Errorf("pack manifest: %w", err)
+	}
+	// custom categories are record fields too
+	for _, d := range m.Categories {
+		if _, ok := gameJSONFields[d.Key]; !ok {
+			m.Fields[d.Key] = d.Kind
+		}
+	}
+
+	return m, nil
+}
+
+// ----------------------------
+// Library
+// ----------------------------
+
+// Library holds every dataset pack found under the packs directory, one Catalog each.
+type Library struct {
+	packs map[string]*Catalog
+	names []string
+	def   string
+}
+
+// OpenLibrary loads every <dir>/<name>/dataset.json. def names the pack used when
+// a request doesn't pick one; it must exist.
+func OpenLibrary(dir, def string) (*Library, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("open packs: %w", err)
+	}
+
+	lib := &Library{packs: map[string]*Catalog{}, def: def}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, e.Name(), packDatasetFile)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		cat, err := OpenCatalog(path)
+		if err != nil {
+			return nil, fmt.Errorf("pack %s: %w", e.Name(), err)
+		}
+		lib.packs[e.Name()] = cat
+		lib.names = append(lib.names, e.Name())
+	}
+	sort.Strings(lib.names)
+
+	if lib.packs[def] == nil {
+		return nil, fmt.Errorf("default pack %q not found in %s", def, dir)
+	}
+	return lib, nil
+}
+
+// Pack returns the named pack's catalog, or the default pack for "". Unknown names give nil.
+func (l *Library) Pack(name string) *Catalog {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = l.def
+	}
+	return l.packs[name]
+}
+
+// Resolve picks the pack named by the ?pack= query parameter.
+func (l *Library) Resolve(r *http.Request) (*Catalog, error) {
+	name := r.URL.Query().Get("pack")
+	cat := l.Pack(name)
+	if cat == nil {
+		return nil, fmt.Errorf("unknown pack %q", name)
+	}
+	return cat, nil
+}
+
+func (l *Library) Names() []string {
+	return l.names
+}
+
+func (l *Library) Default() string {
+	return l.def
+}
+
+func (l *Library) ReloadAll() {
+	for _, name := range l.names {
+		l.packs[name].Reload()
+	}
+}
+
+func (l *Library) Watch(interval time.Duration, stop <-chan struct{}) {
+	for _, name := range l.names {
+		go l.packs[name].Watch(interval, stop)
+	}
+}
+
+// ----------------------------
+// API: pack list
+// ----------------------------
+
+type PackInfo struct {
+	Name       string   `json:"name"`
+	Label      string   `json:"label"`
+	Games      int      `json:"games"`
+	Categories []string `json:"categories"`
+	Default    bool     `json:"default"`
+}
+
+func PacksHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		out := []PackInfo{}
+		for _, name := range lib.Names() {
+			idx := lib.Pack(name).Index()
+			label := idx.Manifest.Label
+			if label == "" {
+				label = name
+			}
+			out = append(out, PackInfo{
+				Name:       name,
+				Label:      label,
+				Games:      idx.Size(),
+				Categories: idx.Categories(),
+				Default:    name == lib.Default(),
+			})
+		}
+
+		json.NewEncoder(w).Encode(struct {
+			Packs []PackInfo `json:"packs"`
+		}{Packs: out})
+	})
+}
@@ -0,0 +1,211 @@
+package guesser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	// values listed per facet, most common first; selected values are always listed
+	searchFacetValues = 25
+)
+
+// facets counted when the request doesn't name any with ?facets=
+var defaultSearchFacets = []string{
+	"primary_genre", "sub_genres", "platforms", "year",
+	"camera_view", "multiplayer_presence", "world_type", "overall_tone",
+}
+
+// query parameters /api/search reserves; any other category name is a facet filter
+var searchReserved = map[string]bool{
+	"pack": true, "q": true, "facets": true, "fields": true, "sort": true, "limit": true, "page": true,
+}
+
+type SearchFacetValue struct {
+	Value    string `json:"value"`
+	Count    int    `json:"count"`
+	Selected bool   `json:"selected,omitempty"`
+}
+
+type SearchFacet struct {
+	Field  string             `json:"field"`
+	Label  string             `json:"label"`
+	Values []SearchFacetValue `json:"values"`
+}
+
+type SearchResponse struct {
+	Query   string                   `json:"query"`
+	Total   int                      `json:"total"`
+	Results []map[string]interface{} `json:"results"`
+	Facets  []SearchFacet            `json:"facets"`
+}
+
+// SearchHandler serves /api/search for the ?pack= dataset:
+//
+//	?q=           words matched against names, series and list categories (tags, features...)
+//	?<category>=  facet filter; repeat for OR within a category, categories combine with AND
+//	?facets=      categories to count (comma-separated, or "all")
+//	?sort=        relevance (default with q), name, year, id; "-" for descending
+//	?limit=&page= and ?fields= as on the explore endpoints
+//
+// Each facet is counted with every filter applied except its own, so the sidebar
+// shows how many results picking another value would give.
+func SearchHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+		idx := cat.Index()
+		si := idx.search
+		query := r.URL.Query()
+
+		views, err := newGameViews(r, idx)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+
+		text := strings.TrimSpace(query.Get("q"))
+		hits, scores := si.matchText(text)
+
+		// facet filters
+		filters := map[string]bitset{}
+		selected := map[string]map[string]bool{}
+		for key, vals := range query {
+			if searchReserved[key] {
+				continue
+			}
+			// a typo would otherwise quietly return everything
+			if _, ok := idx.Registry.Def(key); !ok {
+				http.Error(w, fmt.Sprintf("unknown filter %q", key), 400)
+				return
+			}
+			filters[key] = si.matchFacet(key, vals)
+			selected[key] = map[string]bool{}
+			for _, v := range vals {
+				selected[key][strings.ToLower(strings.TrimSpace(v))] = true
+			}
+		}
+
+		results := hits
+		for _, f := range filters {
+			results = results.and(f)
+		}
+
+		// ---- facet counts
+		facetKeys := defaultSearchFacets
+		switch f := query.Get("facets"); f {
+		case "":
+		case "all":
+			facetKeys = idx.Registry.Keys()
+		default:
+			facetKeys = strings.Split(f, ",")
+		}
+
+		facets := []SearchFacet{}
+		for _, key := range facetKeys {
+			key = strings.TrimSpace(key)
+			def, ok := idx.Registry.Def(key)
+			if !ok {
+				continue
+			}
+			base := hits
+			for other, f := range filters {
+				if other != key {
+					base = base.and(f)
+				}
+			}
+
+			facet := SearchFacet{Field: key, Label: def.Label, Values: []SearchFacetValue{}}
+			for k, fv := range si.facets[key] {
+				n := base.and(fv.games).count()
+				sel := selected[key][k]
+				if n == 0 && !sel {
+					continue
+				}
+				facet.Values = append(facet.Values, SearchFacetValue{Value: fv.label, Count: n, Selected: sel})
+			}
+			sort.Slice(facet.Values, func(a, b int) bool {
+				va, vb := facet.Values[a], facet.Values[b]
+				if va.Count != vb.Count {
+					return va.Count > vb.Count
+				}
+				return byText(va.Value, vb.Value)
+			})
+			facet.Values = trimFacetValues(facet.Values)
+			facets = append(facets, facet)
+		}
+
+		// ---- results
+		var games []*Game
+		relevance := map[int]float64{}
+		results.each(func(i int) {
+			games = append(games, idx.Games[i])
+			relevance[idx.Games[i].ID] = scores[i]
+		})
+
+		sortKey := query.Get("sort")
+		if sortKey == "" && text != "" {
+			sortKey = "relevance"
+		}
+		if sortKey == "relevance" {
+			sort.SliceStable(games, func(a, b int) bool {
+				ga, gb := games[a], games[b]
+				sa, sb := relevance[ga.ID], relevance[gb.ID]
+				if sa != sb {
+					return sa > sb
+				}
+				return byText(ga.Name, gb.Name)
+			})
+		} else {
+			eq := &exploreQuery{sortKey: "name"}
+			if sortKey != "" {
+				eq.desc = strings.HasPrefix(sortKey, "-")
+				eq.sortKey = strings.TrimPrefix(sortKey, "-")
+			}
+			if eq.sortKey != "name" && eq.sortKey != "year" && eq.sortKey != "id" {
+				http.Error(w, "cannot sort by "+strconv.Quote(sortKey)+" (use relevance, name, year or id)", 400)
+				return
+			}
+			eq.sortGames(games)
+		}
+
+		limit := defaultSearchLimit
+		if n, err := strconv.Atoi(query.Get("limit")); err == nil && n > 0 {
+			limit = min(n, maxSearchLimit)
+		}
+		offset := 0
+		if n, err := strconv.Atoi(query.Get("page")); err == nil && n > 1 {
+			offset = (n - 1) * limit
+		}
+
+		out := SearchResponse{Query: text, Total: len(games), Results: []map[string]interface{}{}, Facets: facets}
+		for i := offset; i < len(games) && i < offset+limit; i++ {
+			out.Results = append(out.Results, views.of(games[i]))
+		}
+
+		json.NewEncoder(w).Encode(out)
+	})
+}
+
+// trimFacetValues keeps the most common values plus any selected ones further down.
+func trimFacetValues(vals []SearchFacetValue) []SearchFacetValue {
+	if len(vals) <= searchFacetValues {
+		return vals
+	}
+	out := append([]SearchFacetValue{}, vals[:searchFacetValues]...)
+	for _, v := range vals[searchFacetValues:] {
+		if v.Selected {
+			out = append(out, v)
+		}
+	}
+	return out
+}
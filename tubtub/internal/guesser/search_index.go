@@ -0,0 +1,237 @@
+package guesser
+
+import (
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ----------------------------
+// Bitset
+// ----------------------------
+
+// bitset marks positions in Index.Games.
+type bitset []uint64
+
+func newBitset(n int) bitset {
+	return make(bitset, (n+63)/64)
+}
+
+func fullBitset(n int) bitset {
+	b := newBitset(n)
+	for i := 0; i < n; i++ {
+		b.set(i)
+	}
+	return b
+}
+
+func (b bitset) set(i int) {
+	b[i/64] |= 1 << (uint(i) % 64)
+}
+
+func (b bitset) has(i int) bool {
+	return b[i/64]&(1<<(uint(i)%64)) != 0
+}
+
+func (b bitset) and(o bitset) bitset {
+	out := make(bitset, len(b))
+	for i := range b {
+		out[i] = b[i] & o[i]
+	}
+	return out
+}
+
+func (b bitset) or(o bitset) bitset {
+	out := make(bitset, len(b))
+	for i := range b {
+		out[i] = b[i] | o[i]
+	}
+	return out
+}
+
+func (b bitset) count() int {
+	n := 0
+	for _, w := range b {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+func (b bitset) each(fn func(i int)) {
+	for wi, w := range b {
+		for w != 0 {
+			t := bits.TrailingZeros64(w)
+			fn(wi*64 + t)
+			w &= w - 1
+		}
+	}
+}
+
+// ----------------------------
+// Search index
+// ----------------------------
+
+// text fields outside the list categories, and how much a hit in each counts
+var searchTextWeights = map[string]float64{
+	"name":   3,
+	"series": 2,
+}
+
+var searchStopWords = map[string]bool{
+	"the": true, "of": true, "a": true, "an": true, "and": true,
+}
+
+// facetValue is one value of a facet field and the games that have it.
+type facetValue struct {
+	label string
+	games bitset
+}
+
+// searchIndex is built once per Index: text tokens to weighted game positions, and
+// every category value to the games holding it.
+type searchIndex struct {
+	size   int
+	tokens []string // sorted, for prefix lookups
+	text   map[string]map[int]float64
+	// category key -> lowercased value -> games
+	facets map[string]map[string]*facetValue
+}
+
+// searchTokens splits text the way names are compared elsewhere: each word is
+// norm()'d ("Counter-Strike" -> "counterstrike") and hyphenated words also yield
+// their parts so "counter strike" finds it too.
+func searchTokens(s string) []string {
+	var out []string
+	for _, word := range strings.Fields(strings.ToLower(s)) {
+		if w := norm(word); w != "" && !searchStopWords[w] {
+			out = append(out, w)
+		}
+		parts := strings.FieldsFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if len(parts) > 1 {
+			for _, p := range parts {
+				if !searchStopWords[p] {
+					out = append(out, p)
+				}
+			}
+		}
+	}
+	return out
+}
+
+func buildSearchIndex(reg *CategoryRegistry, games []*Game) *searchIndex {
+	si := &searchIndex{
+		size:   len(games),
+		text:   map[string]map[int]float64{},
+		facets: map[string]map[string]*facetValue{},
+	}
+
+	addText := func(i int, s string, w float64) {
+		for _, t := range searchTokens(s) {
+			if si.text[t] == nil {
+				si.text[t] = map[int]float64{}
+			}
+			// a word repeated within one field doesn't count twice
+			si.text[t][i] = max(si.text[t][i], w)
+		}
+	}
+
+	for i, g := range games {
+		addText(i, g.Name, searchTextWeights["name"])
+		addText(i, CleanString(g.Series), searchTextWeights["series"])
+
+		for _, d := range reg.Defs() {
+			vals := reg.Values(g, d.Key)
+			if d.Kind == KindList {
+				for _, v := range vals {
+					addText(i, v, 1)
+				}
+			}
+
+			if si.facets[d.Key] == nil {
+				si.facets[d.Key] = map[string]*facetValue{}
+			}
+			for _, v := range vals {
+				k := strings.ToLower(v)
+				fv := si.facets[d.Key][k]
+				if fv == nil {
+					fv = &facetValue{label: v, games: newBitset(len(games))}
+					si.facets[d.Key][k] = fv
+				}
+				fv.games.set(i)
+			}
+		}
+	}
+
+	for t := range si.text {
+		si.tokens = append(si.tokens, t)
+	}
+	sort.Strings(si.tokens)
+	return si
+}
+
+// matchText scores games against every query word (AND). The last word also
+// matches as a prefix so results follow the user while typing. An empty q matches
+// everything; one made only of stop words matches nothing.
+func (si *searchIndex) matchText(q string) (bitset, map[int]float64) {
+	words := searchTokens(q)
+	scores := map[int]float64{}
+	if strings.TrimSpace(q) == "" {
+		return fullBitset(si.size), scores
+	}
+	if len(words) == 0 {
+		return newBitset(si.size), scores
+	}
+
+	hits := fullBitset(si.size)
+	for wi, w := range words {
+		matched := []string{w}
+		if wi == len(words)-1 {
+			matched = si.prefixed(w)
+		}
+
+		word := newBitset(si.size)
+		for _, t := range matched {
+			for i, weight := range si.text[t] {
+				word.set(i)
+				if t != w {
+					weight /= 2 // prefix hits rank below whole-word hits
+				}
+				scores[i] += weight
+			}
+		}
+		hits = hits.and(word)
+	}
+	return hits, scores
+}
+
+func (si *searchIndex) prefixed(p string) []string {
+	start := sort.SearchStrings(si.tokens, p)
+	var out []string
+	for i := start; i < len(si.tokens) && strings.HasPrefix(si.tokens[i], p); i++ {
+		out = append(out, si.tokens[i])
+	}
+	return out
+}
+
+// matchFacet is the games having any of the wanted values; numeric categories also
+// take lo..hi ranges.
+func (si *searchIndex) matchFacet(key string, wanted []string) bitset {
+	out := newBitset(si.size)
+	for _, w := range wanted {
+		if fv := si.facets[key][strings.ToLower(strings.TrimSpace(w))]; fv != nil {
+			out = out.or(fv.games)
+			continue
+		}
+		if !strings.Contains(w, "..") {
+			continue
+		}
+		for k, fv := range si.facets[key] {
+			if n, err := strconv.Atoi(k); err == nil && intMatches(n, w) {
+				out = out.or(fv.games)
+			}
+		}
+	}
+	return out
+}
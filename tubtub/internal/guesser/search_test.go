@@ -0,0 +1,57 @@
+package guesser
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+const searchFixture = `[
+{"id": 1, "name": "The Witness", "year": 2016, "platforms": ["PC"]},
+{"id": 2, "name": "Portal", "year": 2007, "platforms": ["PC", "Xbox 360"]},
+{"id": 3, "name": "Portal 2", "year": 2011, "platforms": ["PC"]}
+]`
+
+func TestSearchHandler(t *testing.T) {
+	path := writePack(t, searchFixture, "")
+	lib, err := OpenLibrary(filepath.Dir(filepath.Dir(path)), "games")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := SearchHandler(lib)
+
+	tests := []struct {
+		query  string
+		status int
+		total  int
+	}{
+		{query: "", status: 200, total: 3},
+		{query: "q=portal", status: 200, total: 2},
+		{query: "q=the+witness", status: 200, total: 1},
+		{query: "q=the", status: 200, total: 0},
+		{query: "q=the+of+a", status: 200, total: 0},
+		{query: "q=portal&platforms=xbox+360", status: 200, total: 1},
+		{query: "platfrom=PC", status: 400},
+		{query: "q=portal&colour=red", status: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/search?"+tt.query, nil))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status != 200 {
+				return
+			}
+			var res SearchResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Total != tt.total {
+				t.Fatalf("total = %d, want %d", res.Total, tt.total)
+			}
+		})
+	}
+}
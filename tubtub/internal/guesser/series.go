@@ -0,0 +1,165 @@
+package guesser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// categories named in an entry's change summary, heaviest first
+const seriesSummaryFields = 5
+
+// fields left out of the changes between entries: they differ every time or name
+// the series itself
+var seriesDiffSkip = map[string]bool{
+	"series": true,
+	"year":   true,
+}
+
+// SeriesInfo is one franchise in the /api/series list.
+type SeriesInfo struct {
+	Series  string `json:"series"`
+	Entries int    `json:"entries"`
+	First   int    `json:"first"`
+	Last    int    `json:"last"`
+}
+
+// SeriesChange is one category that differs from the previous entry. Lists report
+// what was added and removed, single values what they changed from and to.
+type SeriesChange struct {
+	Field   string   `json:"field"`
+	Label   string   `json:"label"`
+	From    string   `json:"from,omitempty"`
+	To      string   `json:"to,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// SeriesStep is an entry and how it differs from the one released before it. The
+// first entry has no changes.
+type SeriesStep struct {
+	SeriesEntry
+	Summary string         `json:"summary"`
+	Changes []SeriesChange `json:"changes"`
+}
+
+type SeriesDetail struct {
+	Series  string       `json:"series"`
+	First   int          `json:"first"`
+	Last    int          `json:"last"`
+	Entries []SeriesStep `json:"entries"`
+}
+
+// seriesChanges compares two consecutive entries. A category either entry has no
+// value for is skipped: missing data isn't a change.
+func seriesChanges(reg *CategoryRegistry, prev, g *Game) []SeriesChange {
+	out := []SeriesChange{}
+	for _, d := range reg.Defs() {
+		if seriesDiffSkip[d.Key] {
+			continue
+		}
+		a, b := reg.Values(prev, d.Key), reg.Values(g, d.Key)
+		if len(a) == 0 || len(b) == 0 {
+			continue
+		}
+		if d.Kind != KindList {
+			if !strings.EqualFold(a[0], b[0]) {
+				out = append(out, SeriesChange{Field: d.Key, Label: d.Label, From: a[0], To: b[0]})
+			}
+			continue
+		}
+
+		inA, inB := lowerSet(a), lowerSet(b)
+		c := SeriesChange{Field: d.Key, Label: d.Label}
+		for _, v := range b {
+			if !inA[strings.ToLower(v)] {
+				c.Added = append(c.Added, v)
+			}
+		}
+		for _, v := range a {
+			if !inB[strings.ToLower(v)] {
+				c.Removed = append(c.Removed, v)
+			}
+		}
+		if len(c.Added) > 0 || len(c.Removed) > 0 {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func seriesChangeSummary(changes []SeriesChange) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	heaviest := append([]SeriesChange(nil), changes...)
+	sort.SliceStable(heaviest, func(x, y int) bool {
+		return similarityWeight(heaviest[x].Field) > similarityWeight(heaviest[y].Field)
+	})
+	var labels []string
+	for _, c := range heaviest[:min(len(heaviest), seriesSummaryFields)] {
+		labels = append(labels, strings.ToLower(c.Label))
+	}
+	s := "changed: " + strings.Join(labels, ", ")
+	if n := len(changes) - len(labels); n > 0 {
+		s += fmt.Sprintf(" and %d more", n)
+	}
+	return s
+}
+
+func seriesInfo(t SeriesTimeline) SeriesInfo {
+	return SeriesInfo{Series: t.Series, Entries: len(t.Entries), First: t.First, Last: t.Last}
+}
+
+// ----------------------------
+// API: series
+// ----------------------------
+
+// SeriesHandler serves the ?pack= dataset's franchises:
+//
+//	/api/series          every series with its entry count and year span, most entries first
+//	/api/series/{name}   one series' entries in release order, each with what changed
+//	                     since the previous entry
+func SeriesHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+		idx := cat.Index()
+
+		name := strings.TrimSpace(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/series"), "/"))
+		if name == "" {
+			list := []SeriesInfo{}
+			for _, t := range seriesTimelines(idx, "") {
+				list = append(list, seriesInfo(t))
+			}
+			sort.SliceStable(list, func(a, b int) bool { return list[a].Entries > list[b].Entries })
+			json.NewEncoder(w).Encode(struct {
+				Pack   string       `json:"pack"`
+				Series []SeriesInfo `json:"series"`
+			}{Pack: cat.Name(), Series: list})
+			return
+		}
+
+		games := seriesGames(idx)[strings.ToLower(name)]
+		if len(games) == 0 {
+			http.Error(w, "series not found", 404)
+			return
+		}
+		t := seriesTimeline(idx, games)
+		out := SeriesDetail{Series: t.Series, First: t.First, Last: t.Last, Entries: []SeriesStep{}}
+		for i, e := range t.Entries {
+			step := SeriesStep{SeriesEntry: e, Changes: []SeriesChange{}}
+			if i > 0 {
+				step.Changes = seriesChanges(idx.Registry, games[i-1], games[i])
+				step.Summary = seriesChangeSummary(step.Changes)
+			}
+			out.Entries = append(out.Entries, step)
+		}
+		json.NewEncoder(w).Encode(out)
+	})
+}
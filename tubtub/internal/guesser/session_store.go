@@ -18,14 +18,12 @@ var (
 type SessionStore struct {
 	mu       sync.RWMutex
 	sessions map[string]*Session
-	idx      *Index
 }
 
-func NewSessionStore(idx *Index) *SessionStore {
+func NewSessionStore() *SessionStore {
 	rand.Seed(time.Now().UnixNano())
 	return &SessionStore{
 		sessions: make(map[string]*Session),
-		idx:      idx,
 	}
 }
 
@@ -35,12 +33,14 @@ func newSessionID() string {
 	return hex.EncodeToString(b[:])
 }
 
-func (s *SessionStore) CreateSession() (*Session, error) {
-	if s.idx.Size() == 0 {
+// CreateSession starts a round with a random game from the given pack.
+func (s *SessionStore) CreateSession(cat *Catalog) (*Session, error) {
+	idx := cat.Index()
+	if idx.Size() == 0 {
 		return nil, errors.New("dataset empty")
 	}
 
-	game := s.idx.Games[rand.Intn(s.idx.Size())]
+	game := idx.Games[rand.Intn(idx.Size())]
 
 	sess := &Session{
 		ID:             newSessionID(),
@@ -50,6 +50,8 @@ func (s *SessionStore) CreateSession() (*Session, error) {
 		MaxReveals:     10,
 		UsedCategories: make(map[string]bool),
 		BlurPath:       "",
+		Pack:           cat.Name(),
+		mystery:        game,
 	}
 
 	s.mu.Lock()
@@ -60,6 +62,21 @@ func (s *SessionStore) CreateSession() (*Session, error) {
 	return sess, nil
 }
 
+// PackIndex is the live index of the pack the session was started in.
+func (s *Session) PackIndex(lib *Library) *Index {
+	return lib.Pack(s.Pack).Index()
+}
+
+// MysteryGame resolves the session's game against its pack's live index. If a reload
+// removed the game (or reused its ID for another title) the start-of-round record is used.
+func (s *Session) MysteryGame(lib *Library) *Game {
+	g := s.PackIndex(lib).GameByID(s.MysteryGameID)
+	if g == nil || (s.mystery != nil && norm(g.Name) != norm(s.mystery.Name)) {
+		return s.mystery
+	}
+	return g
+}
+
 func (s *SessionStore) GetSession(id string) (*Session, error) {
 	id = strings.TrimSpace(id)
 	s.mu.RLock()
@@ -0,0 +1,231 @@
+package guesser
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// neighbours kept per game when the index loads; also the ?limit= ceiling
+	similarNeighbours   = 20
+	defaultSimilarLimit = 10
+
+	// pulls scores of pairs that only share a few filled-in fields towards zero
+	similarShrink = 2.0
+
+	// shared values named in a neighbour's one-line summary
+	similarSummaryValues = 5
+
+	// two games both lacking something (crafting "None") is weak evidence they are alike
+	sharedAbsenceWeight = 0.25
+)
+
+// similarityWeights says how much each built-in category counts towards "plays like".
+// Gameplay fields count most; presentation and metadata least. Categories not listed
+// here, including a pack's custom ones, weigh 1.
+var similarityWeights = map[string]float64{
+	"primary_genre":        3,
+	"sub_genres":           3,
+	"game_structure":       2.5,
+	"combat_style":         2,
+	"progression_type":     2,
+	"special_mechanics":    2,
+	"movement_type":        1.5,
+	"vibe_tags":            1.5,
+	"world_type":           1.5,
+	"multiplayer_type":     1.5,
+	"crafting_system":      1.5,
+	"loot_system":          1.5,
+	"platforms":            0.5,
+	"visual_style":         0.5,
+	"color_palette":        0.5,
+	"camera_behavior":      0.5,
+	"iconic_features":      0.5,
+	"protagonist_identity": 0.5,
+	"world_origin":         0.5,
+}
+
+// SharedField is what two similar games have in common in one category.
+type SharedField struct {
+	Field  string   `json:"field"`
+	Label  string   `json:"label"`
+	Values []string `json:"values"`
+}
+
+// SimilarGame is one precomputed neighbour of a game.
+type SimilarGame struct {
+	GameSummary
+	Score   float64       `json:"score"`
+	Summary string        `json:"summary"`
+	Shared  []SharedField `json:"shared"`
+}
+
+func similarityWeight(key string) float64 {
+	if w, ok := similarityWeights[key]; ok {
+		return w
+	}
+	return 1
+}
+
+// gameFeatures holds one game's clean values per non-numeric category.
+type gameFeatures map[string][]string
+
+func featuresOf(reg *CategoryRegistry, g *Game) gameFeatures {
+	f := gameFeatures{}
+	for _, d := range reg.Defs() {
+		if d.Kind == KindInt {
+			continue
+		}
+		if vals := reg.Values(g, d.Key); len(vals) > 0 {
+			f[d.Key] = vals
+		}
+	}
+	return f
+}
+
+// isAbsent reports whether a value is the dataset's explicit "None".
+func isAbsent(vals []string) bool {
+	return len(vals) == 1 && strings.EqualFold(vals[0], "none")
+}
+
+func lowerSet(vals []string) map[string]bool {
+	set := make(map[string]bool, len(vals))
+	for _, v := range vals {
+		set[strings.ToLower(v)] = true
+	}
+	return set
+}
+
+// similarity scores two games between 0 and 1: weighted Jaccard over list categories,
+// exact match on the rest, averaged over the categories both games fill in.
+// Matching "None"s count for little and are left out of the explanation.
+func similarity(reg *CategoryRegistry, a, b gameFeatures) float64 {
+	num, den := 0.0, 0.0
+	for _, d := range reg.Defs() {
+		va, vb := a[d.Key], b[d.Key]
+		if len(va) == 0 || len(vb) == 0 {
+			continue
+		}
+		w := similarityWeight(d.Key)
+		if isAbsent(va) && isAbsent(vb) {
+			w *= sharedAbsenceWeight
+		}
+		den += w
+		if d.Kind == KindList {
+			num += w * jaccard(va, vb)
+		} else if strings.EqualFold(va[0], vb[0]) {
+			num += w
+		}
+	}
+	if den == 0 {
+		return 0
+	}
+	return num / (den + similarShrink)
+}
+
+// sharedFields lists the values a and b have in common, heaviest categories first.
+func sharedFields(reg *CategoryRegistry, a, b gameFeatures) []SharedField {
+	out := []SharedField{}
+	for _, d := range reg.Defs() {
+		va, vb := a[d.Key], b[d.Key]
+		if len(va) == 0 || len(vb) == 0 || isAbsent(va) {
+			continue
+		}
+		inB := lowerSet(vb)
+		var common []string
+		for _, v := range va {
+			if inB[strings.ToLower(v)] {
+				common = append(common, v)
+			}
+		}
+		if len(common) > 0 {
+			out = append(out, SharedField{Field: d.Key, Label: d.Label, Values: common})
+		}
+	}
+	sort.SliceStable(out, func(x, y int) bool {
+		return similarityWeight(out[x].Field) > similarityWeight(out[y].Field)
+	})
+	return out
+}
+
+func sharedSummary(shared []SharedField) string {
+	var vals []string
+	seen := map[string]bool{}
+	for _, s := range shared {
+		for _, v := range s.Values {
+			k := strings.ToLower(v)
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			vals = append(vals, k)
+		}
+	}
+	if len(vals) > similarSummaryValues {
+		vals = vals[:similarSummaryValues]
+	}
+	if len(vals) == 0 {
+		return ""
+	}
+	return "shares: " + strings.Join(vals, ", ")
+}
+
+// buildNeighbours precomputes each game's most similar games, best first.
+func buildNeighbours(reg *CategoryRegistry, games []*Game) map[int][]SimilarGame {
+	feats := make([]gameFeatures, len(games))
+	for i, g := range games {
+		feats[i] = featuresOf(reg, g)
+	}
+
+	type scored struct {
+		j     int
+		score float64
+	}
+	scores := make([][]scored, len(games))
+	for i := range games {
+		for j := i + 1; j < len(games); j++ {
+			s := similarity(reg, feats[i], feats[j])
+			if s <= 0 {
+				continue
+			}
+			scores[i] = append(scores[i], scored{j, s})
+			scores[j] = append(scores[j], scored{i, s})
+		}
+	}
+
+	out := make(map[int][]SimilarGame, len(games))
+	for i, g := range games {
+		list := scores[i]
+		sort.Slice(list, func(x, y int) bool {
+			if list[x].score != list[y].score {
+				return list[x].score > list[y].score
+			}
+			return games[list[x].j].ID < games[list[y].j].ID
+		})
+		if len(list) > similarNeighbours {
+			list = list[:similarNeighbours]
+		}
+
+		near := make([]SimilarGame, 0, len(list))
+		for _, s := range list {
+			shared := sharedFields(reg, feats[i], feats[s.j])
+			near = append(near, SimilarGame{
+				GameSummary: summarize(games[s.j]),
+				Score:       float64(int(s.score*1000+0.5)) / 1000,
+				Summary:     sharedSummary(shared),
+				Shared:      shared,
+			})
+		}
+		out[g.ID] = near
+	}
+	return out
+}
+
+// Similar returns up to limit precomputed neighbours of the game, best first.
+func (i *Index) Similar(id, limit int) []SimilarGame {
+	near := i.neighbours[id]
+	if limit > 0 && len(near) > limit {
+		near = near[:limit]
+	}
+	return near
+}
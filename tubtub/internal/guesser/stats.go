@@ -0,0 +1,254 @@
+package guesser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// values listed per field unless ?top= says otherwise (0 lists all)
+	defaultStatsTop = 20
+	// rows and columns kept per co-occurrence table
+	cooccurrenceTop = 12
+)
+
+// co-occurrence tables returned when ?pairs= is not given
+var defaultStatsPairs = [][2]string{
+	{"primary_genre", "camera_view"},
+	{"primary_genre", "multiplayer_presence"},
+	{"primary_genre", "platforms"},
+}
+
+// fields whose most common values get their own list
+var statsHighlights = []string{"vibe_tags", "special_mechanics"}
+
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// FieldStats describes one category. Coverage is the share of games with a
+// non-placeholder value, the same test clues use.
+type FieldStats struct {
+	Field     string       `json:"field"`
+	Label     string       `json:"label"`
+	Kind      string       `json:"kind"`
+	WithValue int          `json:"withValue"`
+	Coverage  float64      `json:"coverage"`
+	Distinct  int          `json:"distinct"`
+	Values    []ValueCount `json:"values"`
+}
+
+type YearCount struct {
+	Year  int `json:"year"`
+	Count int `json:"count"`
+}
+
+// CooccurrenceTable counts games per (row value, column value). List fields count
+// a game once under each of its values.
+type CooccurrenceTable struct {
+	Rows      string   `json:"rows"`
+	Cols      string   `json:"cols"`
+	RowValues []string `json:"rowValues"`
+	ColValues []string `json:"colValues"`
+	Counts    [][]int  `json:"counts"`
+}
+
+type DatasetStats struct {
+	Pack         string                  `json:"pack"`
+	Games        int                     `json:"games"`
+	Fields       []FieldStats            `json:"fields"`
+	Years        []YearCount             `json:"years"`
+	Top          map[string][]ValueCount `json:"top"`
+	Cooccurrence []CooccurrenceTable     `json:"cooccurrence"`
+}
+
+// valueCounts tallies a category's values, most common first.
+func valueCounts(idx *Index, key string) []ValueCount {
+	counts := map[string]int{}
+	labels := map[string]string{}
+	for _, g := range idx.Games {
+		for _, v := range idx.Registry.Values(g, key) {
+			k := strings.ToLower(v)
+			if _, ok := labels[k]; !ok {
+				labels[k] = v
+			}
+			counts[k]++
+		}
+	}
+
+	out := make([]ValueCount, 0, len(counts))
+	for k, n := range counts {
+		out = append(out, ValueCount{Value: labels[k], Count: n})
+	}
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].Count != out[b].Count {
+			return out[a].Count > out[b].Count
+		}
+		return byText(out[a].Value, out[b].Value)
+	})
+	return out
+}
+
+func topValues(vals []ValueCount, n int) []ValueCount {
+	if n > 0 && len(vals) > n {
+		return vals[:n]
+	}
+	return vals
+}
+
+// yearHistogram counts games per year, filling the years between with zeros so
+// gaps show up on a chart.
+func yearHistogram(idx *Index) []YearCount {
+	counts := map[int]int{}
+	lo, hi := 0, 0
+	for _, g := range idx.Games {
+		y, ok := idx.Registry.Value(g, "year").(int)
+		if !ok {
+			continue
+		}
+		counts[y]++
+		if lo == 0 || y < lo {
+			lo = y
+		}
+		if y > hi {
+			hi = y
+		}
+	}
+
+	out := []YearCount{}
+	for y := lo; lo > 0 && y <= hi; y++ {
+		out = append(out, YearCount{Year: y, Count: counts[y]})
+	}
+	return out
+}
+
+func cooccurrence(idx *Index, rows, cols string) CooccurrenceTable {
+	t := CooccurrenceTable{Rows: rows, Cols: cols, RowValues: []string{}, ColValues: []string{}, Counts: [][]int{}}
+	rowPos, colPos := map[string]int{}, map[string]int{}
+	for _, vc := range topValues(valueCounts(idx, rows), cooccurrenceTop) {
+		rowPos[strings.ToLower(vc.Value)] = len(t.RowValues)
+		t.RowValues = append(t.RowValues, vc.Value)
+	}
+	for _, vc := range topValues(valueCounts(idx, cols), cooccurrenceTop) {
+		colPos[strings.ToLower(vc.Value)] = len(t.ColValues)
+		t.ColValues = append(t.ColValues, vc.Value)
+	}
+	for range t.RowValues {
+		t.Counts = append(t.Counts, make([]int, len(t.ColValues)))
+	}
+
+	for _, g := range idx.Games {
+		for _, rv := range idx.Registry.Values(g, rows) {
+			r, ok := rowPos[strings.ToLower(rv)]
+			if !ok {
+				continue
+			}
+			for _, cv := range idx.Registry.Values(g, cols) {
+				if c, ok := colPos[strings.ToLower(cv)]; ok {
+					t.Counts[r][c]++
+				}
+			}
+		}
+	}
+	return t
+}
+
+// ComputeDatasetStats builds the statistics for one pack.
+func ComputeDatasetStats(idx *Index, pack string, top int, pairs [][2]string) *DatasetStats {
+	st := &DatasetStats{
+		Pack:         pack,
+		Games:        idx.Size(),
+		Fields:       []FieldStats{},
+		Years:        yearHistogram(idx),
+		Top:          map[string][]ValueCount{},
+		Cooccurrence: []CooccurrenceTable{},
+	}
+
+	for _, d := range idx.Registry.Defs() {
+		fs := FieldStats{Field: d.Key, Label: d.Label, Kind: d.Kind}
+		for _, g := range idx.Games {
+			if idx.Registry.HasValue(g, d.Key) {
+				fs.WithValue++
+			}
+		}
+		if st.Games > 0 {
+			fs.Coverage = float64(int(float64(fs.WithValue)/float64(st.Games)*1000+0.5)) / 1000
+		}
+		vals := valueCounts(idx, d.Key)
+		fs.Distinct = len(vals)
+		fs.Values = topValues(vals, top)
+		st.Fields = append(st.Fields, fs)
+	}
+
+	for _, key := range statsHighlights {
+		if _, ok := idx.Registry.Def(key); ok {
+			st.Top[key] = topValues(valueCounts(idx, key), defaultStatsTop)
+		}
+	}
+
+	for _, p := range pairs {
+		st.Cooccurrence = append(st.Cooccurrence, cooccurrence(idx, p[0], p[1]))
+	}
+	return st
+}
+
+// ----------------------------
+// API: dataset statistics
+// ----------------------------
+
+// DatasetStatsHandler serves /api/stats/dataset for the ?pack= dataset.
+// ?top=N limits the values listed per field (0 for all) and
+// ?pairs=primary_genre:camera_view,platforms:year picks the co-occurrence tables.
+func DatasetStatsHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+		idx := cat.Index()
+
+		top := defaultStatsTop
+		if s := r.URL.Query().Get("top"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 {
+				http.Error(w, "invalid top", 400)
+				return
+			}
+			top = n
+		}
+
+		pairs := defaultStatsPairs
+		if s := r.URL.Query().Get("pairs"); s != "" {
+			pairs = nil
+			for _, p := range strings.Split(s, ",") {
+				a, b, ok := strings.Cut(strings.TrimSpace(p), ":")
+				_, okA := idx.Registry.Def(a)
+				_, okB := idx.Registry.Def(b)
+				if !ok || !okA || !okB {
+					http.Error(w, fmt.Sprintf("invalid pair %q (use field:field)", p), 400)
+					return
+				}
+				pairs = append(pairs, [2]string{a, b})
+			}
+		} else {
+			// a pack without these fields just gets no default tables
+			var kept [][2]string
+			for _, p := range pairs {
+				_, okA := idx.Registry.Def(p[0])
+				_, okB := idx.Registry.Def(p[1])
+				if okA && okB {
+					kept = append(kept, p)
+				}
+			}
+			pairs = kept
+		}
+
+		json.NewEncoder(w).Encode(ComputeDatasetStats(idx, cat.Name(), top, pairs))
+	})
+}
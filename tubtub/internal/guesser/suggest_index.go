@@ -0,0 +1,279 @@
+package guesser
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// how a suggestion matched, best first
+const (
+	MatchPrefix    = "prefix"    // the name starts with the query
+	MatchWord      = "word"      // a later word starts with the query
+	MatchSubstring = "substring" // the query appears mid-word
+	MatchFuzzy     = "fuzzy"     // within a few typos of a word (or run of words)
+)
+
+var matchRank = map[string]int{MatchPrefix: 0, MatchWord: 1, MatchSubstring: 2, MatchFuzzy: 3}
+
+// SuggestMatch is one ranked suggestion. Highlights are [start, end) rune offsets
+// into Name covering the matched text.
+type SuggestMatch struct {
+	Name       string   `json:"name"`
+	Match      string   `json:"match"`
+	Highlights [][2]int `json:"highlights"`
+
+	dist int
+	pos  int
+}
+
+// suggestEntry is a name in norm() form, remembering where each rune came from.
+type suggestEntry struct {
+	name  string
+	runes []rune
+	orig  []int // orig[i] is the offset in name of runes[i]
+	words [][2]int
+}
+
+// normWithOffsets applies norm() rune by rune so matches can be mapped back onto
+// the original name.
+func normWithOffsets(name string) ([]rune, []int) {
+	var runes []rune
+	var orig []int
+	src := []rune(name)
+	start, end := 0, len(src)
+	for start < end && unicode.IsSpace(src[start]) {
+		start++
+	}
+	for end > start && unicode.IsSpace(src[end-1]) {
+		end--
+	}
+	for i := start; i < end; i++ {
+		r := unicode.ToLower(src[i])
+		if strings.ContainsRune(normDropped, r) {
+			continue
+		}
+		runes = append(runes, r)
+		orig = append(orig, i)
+	}
+	return runes, orig
+}
+
+// wordSpans returns the [start, end) of each space-separated word.
+func wordSpans(runes []rune) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range runes {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(runes)})
+	}
+	return spans
+}
+
+// paddedTrigrams marks word edges with '$' so a swapped pair of letters in the
+// middle of a word still shares the edge trigrams ("zleda" and "zelda" share "da$").
+func paddedTrigrams(word string) []string {
+	r := []rune("$" + word + "$")
+	var out []string
+	for i := 0; i+3 <= len(r); i++ {
+		out = append(out, string(r[i:i+3]))
+	}
+	return out
+}
+
+// suggestIndex is built once per Index for GuessSuggestHandler.
+type suggestIndex struct {
+	entries  []suggestEntry
+	prefixes map[string][]int // every prefix of every word -> entries
+	trigrams map[string][]int // padded word trigrams -> entries
+}
+
+func buildSuggestIndex(games []*Game) *suggestIndex {
+	si := &suggestIndex{prefixes: map[string][]int{}, trigrams: map[string][]int{}}
+
+	for _, g := range games {
+		name := strings.TrimSpace(g.Name)
+		if name == "" {
+			continue
+		}
+		runes, orig := normWithOffsets(name)
+		e := suggestEntry{name: name, runes: runes, orig: orig, words: wordSpans(runes)}
+		id := len(si.entries)
+		si.entries = append(si.entries, e)
+
+		seenP, seenT := map[string]bool{}, map[string]bool{}
+		for _, w := range e.words {
+			word := runes[w[0]:w[1]]
+			for n := 1; n <= len(word); n++ {
+				if p := string(word[:n]); !seenP[p] {
+					seenP[p] = true
+					si.prefixes[p] = append(si.prefixes[p], id)
+				}
+			}
+			for _, t := range paddedTrigrams(string(word)) {
+				if !seenT[t] {
+					seenT[t] = true
+					si.trigrams[t] = append(si.trigrams[t], id)
+				}
+			}
+		}
+	}
+	return si
+}
+
+// typoAllowance is how many edits a query of n runes may be off by.
+func typoAllowance(n int) int {
+	switch {
+	case n < 4:
+		return 0
+	case n <= 5:
+		return 1
+	case n <= 8:
+		return 2
+	}
+	return 3
+}
+
+func indexRunes(s, sub []rune, from int) int {
+	for i := from; i+len(sub) <= len(s); i++ {
+		match := true
+		for j := range sub {
+			if s[i+j] != sub[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// suggest ranks names for q: name prefix, then word prefix, then substring, then
+// typo matches. Within a rank earlier and shorter names come first.
+func (si *suggestIndex) suggest(q string, limit int) []SuggestMatch {
+	q = strings.Join(strings.Fields(norm(q)), " ")
+	if q == "" {
+		return nil
+	}
+	qr := []rune(q)
+	qWords := strings.Fields(q)
+
+	// candidates: word-prefix hits for the first query word, plus trigram neighbours.
+	// A query too short for trigrams can still match mid-word, so scan every name.
+	cand := map[int]bool{}
+	for _, id := range si.prefixes[qWords[0]] {
+		cand[id] = true
+	}
+	if len(qr) < 3 {
+		for id := range si.entries {
+			cand[id] = true
+		}
+	} else {
+		for _, w := range qWords {
+			for _, t := range paddedTrigrams(w) {
+				for _, id := range si.trigrams[t] {
+					cand[id] = true
+				}
+			}
+		}
+	}
+
+	var out []SuggestMatch
+	for id := range cand {
+		if m, ok := si.entries[id].match(qr, len(qWords)); ok {
+			out = append(out, m)
+		}
+	}
+
+	sort.Slice(out, func(a, b int) bool {
+		ma, mb := out[a], out[b]
+		if ra, rb := matchRank[ma.Match], matchRank[mb.Match]; ra != rb {
+			return ra < rb
+		}
+		if ma.dist != mb.dist {
+			return ma.dist < mb.dist
+		}
+		if ma.pos != mb.pos {
+			return ma.pos < mb.pos
+		}
+		if la, lb := len(ma.Name), len(mb.Name); la != lb {
+			return la < lb
+		}
+		return ma.Name < mb.Name
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func (e *suggestEntry) highlight(start, end int) [][2]int {
+	return [][2]int{{e.orig[start], e.orig[end-1] + 1}}
+}
+
+func (e *suggestEntry) match(qr []rune, qWords int) (SuggestMatch, bool) {
+	m := SuggestMatch{Name: e.name}
+
+	// exact text: prefer an occurrence at a word start
+	first := -1
+	for pos := indexRunes(e.runes, qr, 0); pos >= 0; pos = indexRunes(e.runes, qr, pos+1) {
+		if first < 0 {
+			first = pos
+		}
+		if pos == 0 || unicode.IsSpace(e.runes[pos-1]) {
+			m.Match, m.pos = MatchWord, pos
+			if pos == 0 {
+				m.Match = MatchPrefix
+			}
+			m.Highlights = e.highlight(pos, pos+len(qr))
+			return m, true
+		}
+	}
+	if first >= 0 {
+		m.Match, m.pos = MatchSubstring, first
+		m.Highlights = e.highlight(first, first+len(qr))
+		return m, true
+	}
+
+	// typos: compare against each run of as many words as the query has, both whole
+	// and cut to the query's length (the user may still be typing the last word)
+	allowed := typoAllowance(len(qr))
+	if allowed == 0 {
+		return m, false
+	}
+	best, bestStart, bestEnd := allowed+1, 0, 0
+	q := string(qr)
+	for i := 0; i+qWords <= len(e.words); i++ {
+		start, end := e.words[i][0], e.words[i+qWords-1][1]
+		window := e.runes[start:end]
+		d := editDistance(q, string(window))
+		cutEnd := end
+		if len(window) > len(qr) {
+			if cd := editDistance(q, string(window[:len(qr)])); cd < d {
+				d, cutEnd = cd, start+len(qr)
+			}
+		}
+		if d < best {
+			best, bestStart, bestEnd = d, start, cutEnd
+		}
+	}
+	if best > allowed {
+		return m, false
+	}
+	for bestEnd > bestStart+1 && unicode.IsSpace(e.runes[bestEnd-1]) {
+		bestEnd--
+	}
+	m.Match, m.dist, m.pos = MatchFuzzy, best, bestStart
+	m.Highlights = e.highlight(bestStart, bestEnd)
+	return m, true
+}
@@ -0,0 +1,328 @@
+package guesser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// field whose share per year/decade the timeline reports unless ?field= says otherwise
+	defaultTimelineField = "primary_genre"
+	// values listed per year/decade unless ?top= says otherwise (0 lists all)
+	defaultTimelineTop = 8
+	// field whose first appearances are tracked
+	timelineDebutField = "special_mechanics"
+)
+
+// ValueShare is how many of a period's games have a value, and what fraction of them.
+// List fields count a game once under each value, so shares can add up past 1.
+type ValueShare struct {
+	Value string  `json:"value"`
+	Count int     `json:"count"`
+	Share float64 `json:"share"`
+}
+
+// TimelineBucket is one year or decade ("1990s").
+type TimelineBucket struct {
+	Period string       `json:"period"`
+	Games  int          `json:"games"`
+	Values []ValueShare `json:"values"`
+}
+
+// FirstAppearance is the earliest game carrying a value. Ties on year go to the
+// lowest ID so the answer doesn't move between reloads.
+type FirstAppearance struct {
+	Value string      `json:"value"`
+	Year  int         `json:"year"`
+	Game  GameSummary `json:"game"`
+}
+
+type PlatformSpan struct {
+	Platform string `json:"platform"`
+	First    int    `json:"first"`
+	Last     int    `json:"last"`
+	Games    int    `json:"games"`
+}
+
+type SeriesEntry struct {
+	GameSummary
+	Year int `json:"year"`
+}
+
+// SeriesTimeline is a franchise's entries in release order. Entries without a
+// year sort last.
+type SeriesTimeline struct {
+	Series  string        `json:"series"`
+	First   int           `json:"first"`
+	Last    int           `json:"last"`
+	Entries []SeriesEntry `json:"entries"`
+}
+
+type TimelineResponse struct {
+	Pack      string            `json:"pack"`
+	Field     string            `json:"field"`
+	Years     []TimelineBucket  `json:"years"`
+	Decades   []TimelineBucket  `json:"decades"`
+	Debuts    []FirstAppearance `json:"debuts"`
+	Platforms []PlatformSpan    `json:"platforms"`
+	Series    []SeriesTimeline  `json:"series"`
+}
+
+func gameYear(idx *Index, g *Game) (int, bool) {
+	y, ok := idx.Registry.Value(g, "year").(int)
+	return y, ok
+}
+
+// shareBuckets tallies field per period, most common values first.
+func shareBuckets(idx *Index, field string, top int, periodOf func(year int) string) []TimelineBucket {
+	type tally struct {
+		games  int
+		counts map[string]int
+		labels map[string]string
+	}
+	byPeriod := map[string]*tally{}
+	var order []string
+	for _, g := range idx.Games {
+		y, ok := gameYear(idx, g)
+		if !ok {
+			continue
+		}
+		p := periodOf(y)
+		t, seen := byPeriod[p]
+		if !seen {
+			t = &tally{counts: map[string]int{}, labels: map[string]string{}}
+			byPeriod[p] = t
+			order = append(order, p)
+		}
+		t.games++
+		for _, v := range idx.Registry.Values(g, field) {
+			k := strings.ToLower(v)
+			if _, ok := t.labels[k]; !ok {
+				t.labels[k] = v
+			}
+			t.counts[k]++
+		}
+	}
+	sort.Slice(order, func(a, b int) bool { return byNumber(order[a], order[b]) })
+
+	out := make([]TimelineBucket, 0, len(order))
+	for _, p := range order {
+		t := byPeriod[p]
+		vals := make([]ValueShare, 0, len(t.counts))
+		for k, n := range t.counts {
+			vals = append(vals, ValueShare{
+				Value: t.labels[k],
+				Count: n,
+				Share: float64(int(float64(n)/float64(t.games)*1000+0.5)) / 1000,
+			})
+		}
+		sort.Slice(vals, func(a, b int) bool {
+			if vals[a].Count != vals[b].Count {
+				return vals[a].Count > vals[b].Count
+			}
+			return byText(vals[a].Value, vals[b].Value)
+		})
+		if top > 0 && len(vals) > top {
+			vals = vals[:top]
+		}
+		out = append(out, TimelineBucket{Period: p, Games: t.games, Values: vals})
+	}
+	return out
+}
+
+// firstAppearances lists when each value of field first shows up, oldest first.
+func firstAppearances(idx *Index, field string) []FirstAppearance {
+	first := map[string]*FirstAppearance{}
+	firstID := map[string]int{}
+	for _, g := range idx.Games {
+		y, ok := gameYear(idx, g)
+		if !ok {
+			continue
+		}
+		vals := idx.Registry.Values(g, field)
+		if isAbsent(vals) {
+			continue
+		}
+		for _, v := range vals {
+			k := strings.ToLower(v)
+			if f, ok := first[k]; ok && (f.Year < y || f.Year == y && firstID[k] < g.ID) {
+				continue
+			}
+			first[k] = &FirstAppearance{Value: v, Year: y, Game: summarize(g)}
+			firstID[k] = g.ID
+		}
+	}
+
+	out := make([]FirstAppearance, 0, len(first))
+	for _, f := range first {
+		out = append(out, *f)
+	}
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].Year != out[b].Year {
+			return out[a].Year < out[b].Year
+		}
+		return byText(out[a].Value, out[b].Value)
+	})
+	return out
+}
+
+// platformSpans lists each platform's first and last release year, longest-lived first.
+func platformSpans(idx *Index) []PlatformSpan {
+	spans := map[string]*PlatformSpan{}
+	for _, g := range idx.Games {
+		y, ok := gameYear(idx, g)
+		if !ok {
+			continue
+		}
+		for _, p := range idx.Registry.Values(g, "platforms") {
+			k := strings.ToLower(p)
+			s, ok := spans[k]
+			if !ok {
+				s = &PlatformSpan{Platform: p, First: y, Last: y}
+				spans[k] = s
+			}
+			s.First, s.Last = min(s.First, y), max(s.Last, y)
+			s.Games++
+		}
+	}
+
+	out := make([]PlatformSpan, 0, len(spans))
+	for _, s := range spans {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(a, b int) bool {
+		la, lb := out[a].Last-out[a].First, out[b].Last-out[b].First
+		if la != lb {
+			return la > lb
+		}
+		return byText(out[a].Platform, out[b].Platform)
+	})
+	return out
+}
+
+// seriesGames groups games by their Series value, keyed by the lowercased name.
+// Games marked "None" belong to no series.
+func seriesGames(idx *Index) map[string][]*Game {
+	out := map[string][]*Game{}
+	for _, g := range idx.Games {
+		vals := idx.Registry.Values(g, "series")
+		if len(vals) == 0 || isAbsent(vals) {
+			continue
+		}
+		k := strings.ToLower(vals[0])
+		out[k] = append(out[k], g)
+	}
+	for _, games := range out {
+		sort.SliceStable(games, func(a, b int) bool {
+			ya, okA := gameYear(idx, games[a])
+			yb, okB := gameYear(idx, games[b])
+			if okA != okB {
+				return okA
+			}
+			if ya != yb {
+				return ya < yb
+			}
+			return games[a].ID < games[b].ID
+		})
+	}
+	return out
+}
+
+func seriesTimeline(idx *Index, games []*Game) SeriesTimeline {
+	t := SeriesTimeline{Series: idx.Registry.Values(games[0], "series")[0], Entries: []SeriesEntry{}}
+	for _, g := range games {
+		y, ok := gameYear(idx, g)
+		if ok {
+			if t.First == 0 || y < t.First {
+				t.First = y
+			}
+			t.Last = max(t.Last, y)
+		}
+		t.Entries = append(t.Entries, SeriesEntry{GameSummary: summarize(g), Year: y})
+	}
+	return t
+}
+
+// seriesTimelines lists every series, oldest first; name narrows it to one.
+func seriesTimelines(idx *Index, name string) []SeriesTimeline {
+	out := []SeriesTimeline{}
+	for k, games := range seriesGames(idx) {
+		if name != "" && k != strings.ToLower(name) {
+			continue
+		}
+		out = append(out, seriesTimeline(idx, games))
+	}
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].First != out[b].First {
+			return out[a].First < out[b].First
+		}
+		return byText(out[a].Series, out[b].Series)
+	})
+	return out
+}
+
+// ComputeTimeline builds the timeline for one pack.
+func ComputeTimeline(idx *Index, pack, field string, top int, series string) *TimelineResponse {
+	t := &TimelineResponse{
+		Pack:      pack,
+		Field:     field,
+		Years:     shareBuckets(idx, field, top, strconv.Itoa),
+		Decades:   shareBuckets(idx, field, top, func(y int) string { return fmt.Sprintf("%ds", y/10*10) }),
+		Debuts:    []FirstAppearance{},
+		Platforms: []PlatformSpan{},
+		Series:    seriesTimelines(idx, series),
+	}
+	if _, ok := idx.Registry.Def(timelineDebutField); ok {
+		t.Debuts = firstAppearances(idx, timelineDebutField)
+	}
+	if _, ok := idx.Registry.Def("platforms"); ok {
+		t.Platforms = platformSpans(idx)
+	}
+	return t
+}
+
+// ----------------------------
+// API: timeline
+// ----------------------------
+
+// TimelineHandler serves /api/explore/timeline for the ?pack= dataset: the share of
+// ?field= values (default primary_genre) per year and decade, when each special
+// mechanic first appeared, platform lifespans and series timelines.
+// ?top=N limits the values per period (0 for all) and ?series= picks one series.
+func TimelineHandler(lib *Library) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cat, err := lib.Resolve(r)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+		idx := cat.Index()
+		q := r.URL.Query()
+
+		field := defaultTimelineField
+		if s := strings.TrimSpace(q.Get("field")); s != "" {
+			d, ok := idx.Registry.Def(s)
+			if !ok || d.Kind == KindInt {
+				http.Error(w, fmt.Sprintf("invalid field %q", s), 400)
+				return
+			}
+			field = d.Key
+		}
+
+		top := defaultTimelineTop
+		if s := q.Get("top"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 {
+				http.Error(w, "invalid top", 400)
+				return
+			}
+			top = n
+		}
+
+		json.NewEncoder(w).Encode(ComputeTimeline(idx, cat.Name(), field, top, strings.TrimSpace(q.Get("series"))))
+	})
+}
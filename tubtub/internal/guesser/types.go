@@ -1,8 +1,11 @@
 package guesser
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
-// Core game data as stored in web/guesser/games.json
+// Core record as stored in web/guesser/packs/<pack>/dataset.json
 type Game struct {
 	ID     int    `json:"id"`
 	Name   string `json:"name"`
@@ -77,6 +80,27 @@ type Game struct {
 
 	// Optional field: keep for compatibility if future datasets include images.
 	ImageURL string `json:"imageUrl"`
+
+	// Custom fields declared by the pack manifest (string, []string or int).
+	Extra map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON writes pack-declared custom fields alongside the built-in ones.
+func (g Game) MarshalJSON() ([]byte, error) {
+	type plain Game
+	data, err := json.Marshal(plain(g))
+	if err != nil || len(g.Extra) == 0 {
+		return data, err
+	}
+
+	var merged map[string]interface{}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	for k, v := range g.Extra {
+		merged[k] = v
+	}
+	return json.Marshal(merged)
 }
 
 // ----------------------------
@@ -94,6 +118,12 @@ type Session struct {
 	UsedCategories map[string]bool
 
 	BlurPath string
+
+	// dataset pack the mystery game was drawn from
+	Pack string
+
+	// record captured at start so a dataset reload can't strand the round
+	mystery *Game
 }
 
 type GameSummary struct {
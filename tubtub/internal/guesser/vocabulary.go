@@ -0,0 +1,221 @@
+package guesser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const vocabularyFile = "vocabulary.json"
+
+// Vocabulary lists the canonical values (and their synonyms) for categorical Game fields.
+//
+// vocabulary.json maps field -> canonical value -> synonyms:
+//
+//	{"camera_view": {"Third-person": ["Third person", "Over-the-shoulder"]}}
+type Vocabulary struct {
+	fields map[string]map[string]string // field -> vocabKey(value) -> canonical
+}
+
+// VocabMiss is a dataset value that is not listed in the vocabulary for its field.
+type VocabMiss struct {
+	Field   string `json:"field"`
+	Value   string `json:"value"`
+	GameIDs []int  `json:"gameIds"`
+}
+
+// vocabKey folds case, dashes and underscores so "Third-person" and "third person" match.
+func vocabKey(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer("-", " ", "_", " ").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func vocabularyPathFor(datasetPath string) string {
+	return filepath.Join(filepath.Dir(datasetPath), vocabularyFile)
+}
+
+// LoadVocabulary reads a vocabulary file. A missing file yields an empty vocabulary.
+// Fields may be Game fields or custom fields declared in the pack manifest.
+func LoadVocabulary(path string, manifest *PackManifest) (*Vocabulary, error) {
+	v := &Vocabulary{fields: map[string]map[string]string{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return v, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open vocabulary: %w", err)
+	}
+
+	var raw map[string]map[string][]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("decode vocabulary: %w", err)
+	}
+
+	for field, values := range raw {
+		_, builtin := gameJSONFields[field]
+		kind := manifest.Fields[field]
+		if !builtin && kind != KindString && kind != KindList {
+			return nil, fmt.Errorf("vocabulary: unknown field %q", field)
+		}
+		m := map[string]string{}
+		for canonical, synonyms := range values {
+			for _, s := range append([]string{canonical}, synonyms...) {
+				k := vocabKey(s)
+				if prev, ok := m[k]; ok && prev != canonical {
+					return nil, fmt.Errorf("vocabulary: %s value %q maps to both %q and %q", field, s, prev, canonical)
+				}
+				m[k] = canonical
+			}
+		}
+		v.fields[field] = m
+	}
+
+	return v, nil
+}
+
+// Canonical returns the canonical spelling of value for field.
+// ok is false when the field has a vocabulary but the value is not in it.
+func (v *Vocabulary) Canonical(field, value string) (string, bool) {
+	m, covered := v.fields[field]
+	if !covered {
+		return value, true
+	}
+	if c, ok := m[vocabKey(value)]; ok {
+		return c, true
+	}
+	return value, false
+}
+
+// Normalize rewrites covered fields of every game to their canonical values
+// and returns the values that could not be mapped.
+func (v *Vocabulary) Normalize(games []*Game) []VocabMiss {
+	misses := map[[2]string]*VocabMiss{}
+
+	miss := func(field, value string, id int) {
+		key := [2]string{field, value}
+		m := misses[key]
+		if m == nil {
+			m = &VocabMiss{Field: field, Value: value}
+			misses[key] = m
+		}
+		m.GameIDs = append(m.GameIDs, id)
+	}
+
+	for field := range v.fields {
+		fi, builtin := gameJSONFields[field]
+		for _, g := range games {
+			if !builtin {
+				v.normalizeExtra(g, field, miss)
+				continue
+			}
+			fv := reflect.ValueOf(g).Elem().Field(fi)
+
+			switch fv.Kind() {
+			case reflect.String:
+				s := fv.String()
+				if CleanString(s) == "" {
+					continue
+				}
+				c, ok := v.Canonical(field, s)
+				if !ok {
+					miss(field, s, g.ID)
+				}
+				fv.SetString(c)
+			case reflect.Slice:
+				for j := 0; j < fv.Len(); j++ {
+					s := fv.Index(j).String()
+					if CleanString(s) == "" {
+						continue
+					}
+					c, ok := v.Canonical(field, s)
+					if !ok {
+						miss(field, s, g.ID)
+					}
+					fv.Index(j).SetString(c)
+				}
+				// several aliases can map to one canonical value
+				fv.Set(reflect.ValueOf(dedupeFold(fv.Interface().([]string))))
+			}
+		}
+	}
+
+	out := make([]VocabMiss, 0, len(misses))
+	for _, m := range misses {
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].Field != out[b].Field {
+			return out[a].Field < out[b].Field
+		}
+		return out[a].Value < out[b].Value
+	})
+	return out
+}
+
+func (v *Vocabulary) normalizeExtra(g *Game, field string, miss func(field, value string, id int)) {
+	canonical := func(s string) string {
+		if CleanString(s) == "" {
+			return s
+		}
+		c, ok := v.Canonical(field, s)
+		if !ok {
+			miss(field, s, g.ID)
+		}
+		return c
+	}
+
+	switch val := g.Extra[field].(type) {
+	case string:
+		g.Extra[field] = canonical(val)
+	case []string:
+		for j := range val {
+			val[j] = canonical(val[j])
+		}
+		g.Extra[field] = dedupeFold(val)
+	}
+}
+
+// dedupeFold drops later repeats of a value, ignoring case.
+func dedupeFold(vals []string) []string {
+	seen := make(map[string]bool, len(vals))
+	out := vals[:0]
+	for _, s := range vals {
+		k := strings.ToLower(s)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		out = append(out, s)
+	}
+	return out
+}
+
+// CheckVocabulary loads a dataset and its sibling vocabulary.json and reports
+// every value that is not covered by the vocabulary.
+func CheckVocabulary(datasetPath string) ([]VocabMiss, error) {
+	data, err := readDataset(datasetPath)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := LoadPackManifest(manifestPathFor(datasetPath))
+	if err != nil {
+		return nil, err
+	}
+	games, _, err := parseDataset(data, manifest)
+	if err != nil {
+		return nil, err
+	}
+	vocab, err := LoadVocabulary(vocabularyPathFor(datasetPath), manifest)
+	if err != nil {
+		return nil, err
+	}
+	return vocab.Normalize(games), nil
+}
@@ -0,0 +1,74 @@
+package webutil
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter allows up to n events per key in each fixed window.
+type RateLimiter struct {
+	n      int
+	window time.Duration
+
+	mu   sync.Mutex
+	hits map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func NewRateLimiter(n int, window time.Duration) *RateLimiter {
+	return &RateLimiter{n: n, window: window, hits: make(map[string]*rateWindow)}
+}
+
+// Allow records an event for key and reports whether it is within the limit.
+func (l *RateLimiter) Allow(key string) bool {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w := l.hits[key]
+	if w == nil || now.Sub(w.start) >= l.window {
+		// keep the map from growing with keys that went quiet
+		if len(l.hits) > 10000 {
+			for k, old := range l.hits {
+				if now.Sub(old.start) >= l.window {
+					delete(l.hits, k)
+				}
+			}
+		}
+		w = &rateWindow{start: now}
+		l.hits[key] = w
+	}
+	if w.count >= l.n {
+		return false
+	}
+	w.count++
+	return true
+}
+
+// ClientIP is the caller's address. X-Forwarded-For is only trusted when the
+// request comes from this machine, i.e. through a local reverse proxy or tunnel,
+// and then only its rightmost entry: the one that proxy appended. Anything to the
+// left of it came from the client.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		fwd := r.Header.Values("X-Forwarded-For")
+		if len(fwd) > 0 {
+			entries := strings.Split(fwd[len(fwd)-1], ",")
+			if last := strings.TrimSpace(entries[len(entries)-1]); last != "" {
+				return last
+			}
+		}
+	}
+	return host
+}
@@ -0,0 +1,35 @@
+package webutil
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name   string
+		remote string
+		xff    []string
+		want   string
+	}{
+		{name: "direct", remote: "203.0.113.5:4000", want: "203.0.113.5"},
+		{name: "direct ignores forwarded", remote: "203.0.113.5:4000", xff: []string{"10.0.0.1"}, want: "203.0.113.5"},
+		{name: "proxy without header", remote: "127.0.0.1:4000", want: "127.0.0.1"},
+		{name: "proxy", remote: "127.0.0.1:4000", xff: []string{"198.51.100.7"}, want: "198.51.100.7"},
+		{name: "proxy takes rightmost", remote: "127.0.0.1:4000", xff: []string{"10.0.0.1, 198.51.100.7"}, want: "198.51.100.7"},
+		{name: "proxy takes last header", remote: "[::1]:4000", xff: []string{"10.0.0.1", "10.0.0.2, 198.51.100.7"}, want: "198.51.100.7"},
+		{name: "proxy with empty entry", remote: "127.0.0.1:4000", xff: []string{"10.0.0.1, "}, want: "127.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			for _, v := range tt.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if got := ClientIP(r); got != tt.want {
+				t.Fatalf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -18,11 +18,25 @@ SSH_OPTS=("-p" "${PI_PORT}")
 
 ssh "${SSH_OPTS[@]}" "${PI_USER}@${PI_HOST}" "mkdir -p '${PI_PATH}'"
 
+# the admin endpoints edit each pack's dataset.json in place and leave backups and
+# an audit log next to it, and finished dream games are saved under guesser/dreams;
+# keep them across deploys. Run scripts/pull_datasets.sh to bring server edits
+# back into git before changing a dataset in the repo.
 rsync -az --delete -e "ssh -p ${PI_PORT}" \
+  --exclude 'guesser/packs/*/dataset.json' \
+  --exclude 'guesser/packs/*/backups/' \
+  --exclude 'guesser/packs/*/audit.jsonl' \
+  --exclude 'guesser/dreams/' \
   "${DIST_DIR}/" \
   "${ROOT_DIR}/web/" \
   "${PI_USER}@${PI_HOST}:${PI_PATH}/"
 
+# new packs still need their dataset; never overwrite a live one
+rsync -az --ignore-existing -e "ssh -p ${PI_PORT}" \
+  --include '*/' --include 'dataset.json' --exclude '*' \
+  "${ROOT_DIR}/web/guesser/packs/" \
+  "${PI_USER}@${PI_HOST}:${PI_PATH}/guesser/packs/"
+
 ssh "${SSH_OPTS[@]}" "${PI_USER}@${PI_HOST}" "sudo systemctl restart tubtub"
 
 echo "Deploy complete"
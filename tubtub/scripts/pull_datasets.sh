@@ -0,0 +1,26 @@
+#!/usr/bin/env bash
+set -euo pipefail
+
+# Deploys never overwrite the live packs' dataset.json (admin edits happen there).
+# This copies them back into web/guesser/packs/ so the edits can be reviewed and
+# committed:
+#
+#   PI_HOST=pi PI_USER=me PI_PATH=/opt/tubtub scripts/pull_datasets.sh
+#   git diff web/guesser/packs/
+#
+# To ship a dataset changed in the repo instead, pull and commit first, then scp it
+# over the live file and POST /api/admin/reload.
+
+: "${PI_HOST:?Set PI_HOST}"
+: "${PI_USER:?Set PI_USER}"
+: "${PI_PATH:?Set PI_PATH}"
+PI_PORT="${PI_PORT:-22}"
+
+ROOT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
+
+rsync -az -e "ssh -p ${PI_PORT}" \
+  --include '*/' --include 'dataset.json' --exclude '*' \
+  "${PI_USER}@${PI_HOST}:${PI_PATH}/guesser/packs/" \
+  "${ROOT_DIR}/web/guesser/packs/"
+
+echo "Pulled live datasets; review with: git diff web/guesser/packs/"
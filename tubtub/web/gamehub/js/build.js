@@ -40,7 +40,8 @@ const CATEGORY_GROUPS = [
     ],
   },
 ];
-const ALL_CATEGORIES = CATEGORY_GROUPS.flatMap((group) =>
+// replaced by /api/dream/categories when the server answers
+let ALL_CATEGORIES = CATEGORY_GROUPS.flatMap((group) =>
   group.categories.map((cat) => ({ ...cat, group: group.title }))
 );
 
@@ -73,6 +74,8 @@ const gradeCreativity = document.getElementById("grade-creativity");
 const gradeTheme = document.getElementById("grade-theme");
 const gradeFinal = document.getElementById("grade-final");
 const resetBtn = document.getElementById("reset-btn");
+const shareBtn = document.getElementById("share-btn");
+const shareLink = document.getElementById("share-link");
 
 // Bail early if the new builder DOM is not present (old cached page)
 if (!rollBtn || !assignBtn || !categoryRows) {
@@ -86,6 +89,8 @@ let pendingGame = null;
 let assignments = {};
 let namePool = [];
 let currentCategories = [];
+// server-side board; null falls back to browser-only rolls
+let dreamSessionId = null;
 const instructionSteps = [
   "This is a game-building simulation. We’ll pull random games from the dataset for you.",
   "Pick the categories you want to evaluate and assign each generated game to one of them.",
@@ -94,6 +99,12 @@ const instructionSteps = [
 let instructionIndex = 0;
 const selectedCategoryIds = new Set();
 
+function escapeHTML(s) {
+  return String(s ?? "").replace(/[&<>"']/g, (c) => ({
+    "&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;", "'": "&#39;",
+  })[c]);
+}
+
 function getCategoryCard(id) {
   return categoryRows?.querySelector(`[data-category-id="${id}"]`);
 }
@@ -143,8 +154,8 @@ function buildCategories(selectedCategories) {
       card.className = "category-card";
       card.dataset.categoryId = cat.id;
       card.innerHTML = `
-        <div class="category-name">${cat.displayLabel || cat.label}</div>
-        <div class="category-desc">${cat.desc}</div>
+        <div class="category-name">${escapeHTML(cat.displayLabel || cat.label)}</div>
+        <div class="category-desc">${escapeHTML(cat.desc)}</div>
       `;
       card.addEventListener("click", () => selectCategory(cat.id));
       grid.appendChild(card);
@@ -261,8 +272,50 @@ function animateShuffle(pool, durationMs = 2000) {
   });
 }
 
+async function startDreamSession(categoryIds) {
+  try {
+    const res = await fetch("/api/dream/sessions", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      // build.html?seed=... gives everyone with the same link the same rolls
+      body: JSON.stringify({
+        categories: categoryIds,
+        seed: new URLSearchParams(location.search).get("seed") || "",
+      }),
+    });
+    if (!res.ok) throw new Error(await res.text());
+    const view = await res.json();
+    return view.sessionId;
+  } catch (err) {
+    console.warn("Dream session unavailable, rolling locally", err);
+    return null;
+  }
+}
+
+async function loadServerCategories() {
+  try {
+    const res = await fetch("/api/dream/categories", { cache: "no-store" });
+    if (!res.ok) throw new Error("Network error");
+    const data = await res.json();
+    if (data.categories?.length) ALL_CATEGORIES = data.categories;
+  } catch (err) {
+    console.warn("Using built-in builder categories", err);
+  }
+}
+
+function traitText(cat, game) {
+  if (cat?.subjective) return "Your call: what does it bring?";
+  return game.trait || "";
+}
+
 async function fetchRandomGame() {
   try {
+    if (dreamSessionId) {
+      const res = await fetch(`/api/dream/sessions/${dreamSessionId}/roll`, { method: "POST", cache: "no-store" });
+      if (!res.ok) throw new Error(await res.text());
+      const view = await res.json();
+      return normalizeGame(view.pending || {});
+    }
     const response = await fetch(`/api/dream/roll?ts=${Date.now()}`, { cache: "no-store" });
     if (!response.ok) throw new Error("Network error");
     const payload = await response.json();
@@ -335,7 +388,7 @@ async function handleRoll() {
   }
 }
 
-function assignGame() {
+async function assignGame() {
   if (!pendingGame) {
     statusText.textContent = "Generate a game first.";
     return;
@@ -351,6 +404,25 @@ function assignGame() {
     return;
   }
 
+  if (dreamSessionId) {
+    assignBtn.disabled = true;
+    try {
+      const res = await fetch(`/api/dream/sessions/${dreamSessionId}/assign`, {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify({ category: selectedCategoryId }),
+      });
+      if (!res.ok) throw new Error((await res.text()).trim());
+      const view = await res.json();
+      const slot = (view.slots || []).find((s) => s.category === selectedCategoryId);
+      pendingGame.trait = slot?.game?.trait || "";
+    } catch (err) {
+      statusText.textContent = `Could not lock it in: ${err.message}`;
+      assignBtn.disabled = false;
+      return;
+    }
+  }
+
   assignments[selectedCategoryId] = pendingGame;
   card.classList.add("locked", "glow-fill");
   card.style.setProperty("--card-color", pendingGame.color);
@@ -366,6 +438,14 @@ function assignGame() {
   assignedEl.textContent = pendingGame.name;
   assignedEl.style.background = `linear-gradient(135deg, ${pendingGame.color}, rgba(255,255,255,0.2))`;
 
+  const trait = traitText(categoryLookup[selectedCategoryId], pendingGame);
+  if (trait) {
+    const traitEl = document.createElement("div");
+    traitEl.className = "assigned-trait";
+    traitEl.textContent = trait;
+    card.appendChild(traitEl);
+  }
+
   pendingGame = null;
   assignBtn.disabled = true;
   assignBtn.classList.remove("glow");
@@ -431,18 +511,45 @@ function showEndPanel() {
     const item = document.createElement("div");
     item.className = "summary-item";
     const assigned = assignments[id];
+    const trait = assigned ? traitText(categoryLookup[id], assigned) : "";
     item.innerHTML = `
-      <div class="label">${categoryLookup[id].displayLabel || categoryLookup[id].label}</div>
-      <div class="value">${assigned ? assigned.name : "Unassigned"}</div>
+      <div class="label">${escapeHTML(categoryLookup[id].displayLabel || categoryLookup[id].label)}</div>
+      <div class="value">${escapeHTML(assigned ? assigned.name : "Unassigned")}</div>
+      ${trait ? `<div class="trait">${escapeHTML(trait)}</div>` : ""}
     `;
     summaryGrid.appendChild(item);
   });
 
+  shareBtn?.classList.toggle("hidden", !dreamSessionId);
+  if (shareBtn) shareBtn.disabled = false;
+  shareLink?.classList.add("hidden");
   endOverlay.classList.remove("hidden");
 }
 
+async function shareDream() {
+  if (!dreamSessionId) return;
+  shareBtn.disabled = true;
+  try {
+    const res = await fetch(`/api/dream/sessions/${dreamSessionId}/share`, {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ expiresInDays: 30 }),
+    });
+    if (!res.ok) throw new Error((await res.text()).trim());
+    const share = await res.json();
+    const url = new URL(share.url, location.origin).href;
+    shareLink.innerHTML = `Share link (30 days): <a href="${url}" target="_blank">${url}</a>`;
+    navigator.clipboard?.writeText(url).catch(() => {});
+  } catch (err) {
+    shareLink.textContent = `Could not create a link: ${err.message}`;
+    shareBtn.disabled = false;
+  }
+  shareLink.classList.remove("hidden");
+}
+
 function resetBuilder() {
   assignments = {};
+  dreamSessionId = null;
   pendingGame = null;
   selectedCategoryId = null;
   statusText.textContent = "Pick a category, generate, then lock it in.";
@@ -462,6 +569,7 @@ function resetBuilder() {
     card.style.removeProperty("border-color");
     const assigned = card.querySelector(".assigned-game");
     if (assigned) assigned.remove();
+    card.querySelector(".assigned-trait")?.remove();
   });
   endOverlay.classList.add("hidden");
   updateProgress();
@@ -482,7 +590,9 @@ function resetBuilder() {
   });
 }
 
-function init() {
+async function init() {
+  await loadServerCategories();
+
   rollBtn.disabled = true;
   assignBtn.disabled = true;
   updateProgress();
@@ -490,6 +600,7 @@ function init() {
   rollBtn.addEventListener("click", handleRoll);
   assignBtn.addEventListener("click", assignGame);
   resetBtn.addEventListener("click", resetBuilder);
+  shareBtn?.addEventListener("click", shareDream);
 
   if (startBtn) {
     startBtn.addEventListener("click", () => {
@@ -562,7 +673,7 @@ function init() {
   }
 
   if (startBuildBtn) {
-    startBuildBtn.addEventListener("click", () => {
+    startBuildBtn.addEventListener("click", async () => {
       const chosen = ALL_CATEGORIES.filter((c) => selectedCategoryIds.has(c.id));
       if (!chosen.length) {
         if (statusText) statusText.textContent = "Pick at least one category to begin.";
@@ -570,6 +681,7 @@ function init() {
       }
       currentCategories = chosen;
       TOTAL_CATEGORIES = currentCategories.length;
+      dreamSessionId = await startDreamSession(chosen.map((c) => c.id));
       assignments = {};
       pendingGame = null;
       selectedCategoryId = null;
@@ -0,0 +1,90 @@
+// Read-only view of a shared dream game: dream.html?s=<slug>
+const dreamTitle = document.getElementById("dream-title");
+const dreamMeta = document.getElementById("dream-meta");
+const dreamSlots = document.getElementById("dream-slots");
+const voteBtn = document.getElementById("vote-btn");
+
+// anonymous ID so each browser gets one vote per dream game
+function playerId() {
+  let id = localStorage.getItem("tubtub-player-id");
+  if (!id) {
+    id = crypto.randomUUID ? crypto.randomUUID() : `p-${Date.now()}-${Math.random().toString(36).slice(2)}`;
+    localStorage.setItem("tubtub-player-id", id);
+  }
+  return id;
+}
+
+function showVotes(votes, voted) {
+  voteBtn.textContent = `${voted ? "▲ Upvoted" : "▲ Upvote"} · ${votes}`;
+  voteBtn.classList.toggle("active", voted);
+  voteBtn.classList.remove("hidden");
+}
+
+async function toggleVote(slug) {
+  const voted = voteBtn.classList.contains("active");
+  voteBtn.disabled = true;
+  try {
+    const res = await fetch(`/api/dream/${encodeURIComponent(slug)}/vote`, {
+      method: voted ? "DELETE" : "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ playerId: playerId() }),
+    });
+    if (res.status === 409) {
+      dreamMeta.textContent = "Someone on your network already upvoted this dream game.";
+      voteBtn.disabled = true;
+      return;
+    }
+    if (!res.ok) throw new Error((await res.text()).trim());
+    const result = await res.json();
+    showVotes(result.votes, result.voted);
+  } catch (err) {
+    dreamMeta.textContent = `Vote failed: ${err.message}`;
+  }
+  voteBtn.disabled = false;
+}
+
+function escapeHTML(s) {
+  return String(s ?? "").replace(/[&<>"']/g, (c) => ({
+    "&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;", "'": "&#39;",
+  })[c]);
+}
+
+async function loadDream() {
+  const slug = new URLSearchParams(location.search).get("s");
+  if (!slug) {
+    dreamTitle.textContent = "No dream game linked.";
+    return;
+  }
+
+  const res = await fetch(`/api/dream/${encodeURIComponent(slug)}`, { cache: "no-store" });
+  if (!res.ok) {
+    dreamTitle.textContent = res.status === 410 ? "This link has expired." : "Dream game not found.";
+    return;
+  }
+  const share = await res.json();
+  const dream = share.dream;
+
+  dreamTitle.textContent = "Dream Game";
+  const built = new Date(dream.finishedAt).toLocaleDateString();
+  dreamMeta.textContent = share.expiresAt
+    ? `Built ${built} · link expires ${new Date(share.expiresAt).toLocaleDateString()}`
+    : `Built ${built}`;
+
+  dreamSlots.innerHTML = "";
+  dream.slots.forEach((slot) => {
+    const item = document.createElement("div");
+    item.className = "summary-item";
+    const game = slot.game || {};
+    item.innerHTML = `
+      <div class="label">${escapeHTML(slot.label)}</div>
+      <div class="value">${escapeHTML(game.trait || game.name || "Unassigned")}</div>
+      ${game.trait ? `<div class="label">from ${escapeHTML(game.name)}</div>` : ""}
+    `;
+    dreamSlots.appendChild(item);
+  });
+
+  showVotes(share.votes || 0, false);
+  voteBtn.addEventListener("click", () => toggleVote(share.slug));
+}
+
+loadDream();
@@ -16,14 +16,14 @@ async function loadExplore(mode) {
 
   results.innerHTML = "";
 
-  Object.keys(data).forEach(group => {
-    data[group].forEach(game => {
+  data.groups.forEach(group => {
+    group.games.forEach(game => {
       const card = document.createElement("div");
       card.className = "card";
       card.innerHTML = `
         <img src="${game.imageUrl}" />
         <h3>${game.name}</h3>
-        <p>${group}</p>
+        <p>${group.key}</p>
       `;
       results.appendChild(card);
     });
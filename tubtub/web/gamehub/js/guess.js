@@ -47,6 +47,7 @@ let shuffling = false;
 let hintCap = 0;
 let currentDifficulty = DIFFICULTIES.easy;
 let instructionIndex = 0;
+let categoryLabels = {};
 
 function resetState() {
   currentGame = null;
@@ -103,6 +104,19 @@ async function fetchRandomGame() {
   }
 }
 
+async function loadCategoryLabels() {
+  try {
+    const res = await fetch("/api/guess/category-meta", { cache: "no-store" });
+    if (!res.ok) throw new Error("network");
+    const data = await res.json();
+    (data.categories || []).forEach((c) => {
+      categoryLabels[c.key] = c.label;
+    });
+  } catch (err) {
+    // fall back to the local label table
+  }
+}
+
 function normalizeGame(raw) {
   const name = raw.name || raw.Name || "Signal";
   const m = raw.metadata || {};
@@ -146,7 +160,7 @@ function buildHintPool(game) {
     if (value) {
       pool.push({
         key,
-        label: labelMap[key] || key,
+        label: categoryLabels[key] || labelMap[key] || key,
         value,
       });
     }
@@ -369,6 +383,7 @@ function bindEvents() {
 
 function init() {
   bindEvents();
+  loadCategoryLabels();
   // gate the experience until difficulty is chosen
   guesserMain?.classList.add("gated");
   startGate?.classList.remove("hidden");